@@ -2,6 +2,7 @@ package fail
 
 import (
 	"fmt"
+	"time"
 )
 
 // Constructors for common patterns
@@ -29,8 +30,11 @@ func FromWithMsg(err error, message string) *Error {
 // Builder methods for Error - these all return *Error for easy chaining
 
 // Clone lets you safely clone a fail.Error
+// Only the Meta map is copied, its values and Args are shared, see DeepClone
+// The clone is never treated as a shared template, see SetCopyOnWrite
 func (e *Error) Clone() *Error {
 	clone := *e
+	clone.shared = false
 	if e.Meta != nil {
 		clone.Meta = make(map[string]any, len(e.Meta))
 		for k, v := range e.Meta {
@@ -42,7 +46,8 @@ func (e *Error) Clone() *Error {
 
 // System marks this error as a system error
 func (e *Error) System() *Error {
-	if e.checkStatic("System") {
+	e, abort := e.prepare("System")
+	if abort {
 		return e
 	}
 	e.IsSystem = true
@@ -51,7 +56,8 @@ func (e *Error) System() *Error {
 
 // Domain marks this error as a domain error
 func (e *Error) Domain() *Error {
-	if e.checkStatic("Domain") {
+	e, abort := e.prepare("Domain")
+	if abort {
 		return e
 	}
 	e.IsSystem = false
@@ -60,7 +66,8 @@ func (e *Error) Domain() *Error {
 
 // Msg sets or overrides the error message (for Dynamic errors)
 func (e *Error) Msg(msg string) *Error {
-	if e.checkStatic("Msg") {
+	e, abort := e.prepare("Msg")
+	if abort {
 		return e
 	}
 	e.Message = msg
@@ -69,7 +76,8 @@ func (e *Error) Msg(msg string) *Error {
 
 // Msgf sets the error message using format string
 func (e *Error) Msgf(format string, args ...any) *Error {
-	if e.checkStatic("Msgf") {
+	e, abort := e.prepare("Msgf")
+	if abort {
 		return e
 	}
 	e.Message = fmt.Sprintf(format, args...)
@@ -78,7 +86,8 @@ func (e *Error) Msgf(format string, args ...any) *Error {
 
 // Internal sets or overrides the internal message
 func (e *Error) Internal(msg string) *Error {
-	if e.checkStatic("Internal") {
+	e, abort := e.prepare("Internal")
+	if abort {
 		return e
 	}
 	e.InternalMessage = msg
@@ -87,7 +96,8 @@ func (e *Error) Internal(msg string) *Error {
 
 // Internalf sets the internal message using format string
 func (e *Error) Internalf(format string, args ...any) *Error {
-	if e.checkStatic("Internalf") {
+	e, abort := e.prepare("Internalf")
+	if abort {
 		return e
 	}
 	e.InternalMessage = fmt.Sprintf(format, args...)
@@ -96,7 +106,8 @@ func (e *Error) Internalf(format string, args ...any) *Error {
 
 // With sets the cause of the error
 func (e *Error) With(cause error) *Error {
-	if e.checkStatic("With") {
+	e, abort := e.prepare("With")
+	if abort {
 		return e
 	}
 	e.Cause = cause
@@ -113,7 +124,8 @@ func (e *Error) With(cause error) *Error {
 
 // WithLocale sets the target locale for this error
 func (e *Error) WithLocale(locale string) *Error {
-	if e.checkStatic("WithLocale") {
+	e, abort := e.prepare("WithLocale")
+	if abort {
 		return e
 	}
 	e.Locale = locale
@@ -122,7 +134,8 @@ func (e *Error) WithLocale(locale string) *Error {
 
 // WithArgs sets the arguments for template formatting
 func (e *Error) WithArgs(args ...any) *Error {
-	if e.checkStatic("WithArgs") {
+	e, abort := e.prepare("WithArgs")
+	if abort {
 		return e
 	}
 	e.Args = args
@@ -131,7 +144,8 @@ func (e *Error) WithArgs(args ...any) *Error {
 
 // WithMeta sets the metadata to data, it replaces existing metadata to merge use MergeMeta
 func (e *Error) WithMeta(data map[string]any) *Error {
-	if e.checkStatic("WithMeta") {
+	e, abort := e.prepare("WithMeta")
+	if abort {
 		return e
 	}
 	e.Meta = data
@@ -140,7 +154,8 @@ func (e *Error) WithMeta(data map[string]any) *Error {
 
 // AddMeta sets a metadata value
 func (e *Error) AddMeta(key string, value any) *Error {
-	if e.checkStatic("AddMeta") {
+	e, abort := e.prepare("AddMeta")
+	if abort {
 		return e
 	}
 	if e.Meta == nil {
@@ -152,7 +167,8 @@ func (e *Error) AddMeta(key string, value any) *Error {
 
 // MergeMeta merges a map into the metadata
 func (e *Error) MergeMeta(data map[string]any) *Error {
-	if e.checkStatic("MergeMeta") {
+	e, abort := e.prepare("MergeMeta")
+	if abort {
 		return e
 	}
 	if e.Meta == nil {
@@ -166,42 +182,62 @@ func (e *Error) MergeMeta(data map[string]any) *Error {
 
 // Trace adds trace information to metadata
 func (e *Error) Trace(trace string) *Error {
-	if e.checkStatic("Trace") {
+	e, abort := e.prepare("Trace")
+	if abort {
 		return e
 	}
-	return e.addToSliceMeta("traces", trace)
+	return e.addToSliceMeta(MetaTraces, trace)
 }
 
 // Traces adds each trace information to metadata
 func (e *Error) Traces(trace ...string) *Error {
-	if e.checkStatic("Traces") {
+	e, abort := e.prepare("Traces")
+	if abort {
 		return e
 	}
 	for _, t := range trace {
-		_ = e.addToSliceMeta("traces", t)
+		_ = e.addToSliceMeta(MetaTraces, t)
 	}
 	return e
 }
 
 // Debug adds debug information to metadata
 func (e *Error) Debug(debug string) *Error {
-	if e.checkStatic("Debug") {
+	e, abort := e.prepare("Debug")
+	if abort {
 		return e
 	}
-	return e.addToSliceMeta("debug", debug)
+	return e.addToSliceMeta(MetaDebug, debug)
 }
 
 // Debugs adds each debug information to metadata
 func (e *Error) Debugs(debug ...string) *Error {
-	if e.checkStatic("Debugs") {
+	e, abort := e.prepare("Debugs")
+	if abort {
 		return e
 	}
 	for _, t := range debug {
-		_ = e.addToSliceMeta("debug", t)
+		_ = e.addToSliceMeta(MetaDebug, t)
 	}
 	return e
 }
 
+// RetryAfter sets a server-provided hint of how long to wait before retrying
+// The Retry* functions prefer this hint over the configured backoff
+// It does not mark the error as retryable, use SetMeta(err, MetaRetryable, true) for that
+// Allowed on static errors, fixed-message 429/503 errors are usually static
+func (e *Error) RetryAfter(d time.Duration) *Error {
+	e, abort := e.prepare("RetryAfter")
+	if abort {
+		return e
+	}
+	if d < 0 {
+		d = 0
+	}
+	setMeta(e, MetaRetryAfter, d)
+	return e
+}
+
 // ValidationError represents a field validation error
 type ValidationError struct {
 	Field   string `json:"field"`
@@ -215,72 +251,40 @@ func NewValidationError(field, msg string) ValidationError {
 
 // Validation adds a validation error to metadata
 func (e *Error) Validation(field, message string) *Error {
-	if e.checkStatic("Validation") {
+	e, abort := e.prepare("Validation")
+	if abort {
 		return e
 	}
-	if e.Meta == nil {
-		e.Meta = make(map[string]any)
-	}
-
-	validations, exists := e.Meta["validations"]
-	if !exists {
-		validations = make([]ValidationError, 0, 1)
-	}
-
-	validationList, ok := validations.([]ValidationError)
-	if !ok {
-		validations = make([]ValidationError, 0, 1)
-		validationList = validations.([]ValidationError)
-	}
-
+	validationList, _ := getMeta(e, MetaValidations)
 	validationList = append(validationList, ValidationError{
 		Field:   field,
 		Message: message,
 	})
 
-	e.Meta["validations"] = validationList
+	setMeta(e, MetaValidations, validationList)
 	return e
 }
 
 // Validations adds multiple validation errors at once
 func (e *Error) Validations(errs []ValidationError) *Error {
-	if e.checkStatic("Validations") {
+	e, abort := e.prepare("Validations")
+	if abort {
 		return e
 	}
-	if e.Meta == nil {
-		e.Meta = make(map[string]any)
-	}
-
-	validations, exists := e.Meta["validations"]
+	validationList, exists := getMeta(e, MetaValidations)
 	if !exists {
-		e.Meta["validations"] = errs
+		setMeta(e, MetaValidations, errs)
 		return e
 	}
 
-	validationList, ok := validations.([]ValidationError)
-	if !ok {
-		validations = make([]ValidationError, 0, 1)
-		validationList = validations.([]ValidationError)
-	}
-
 	validationList = append(validationList, errs...)
-	e.Meta["validations"] = validationList
+	setMeta(e, MetaValidations, validationList)
 	return e
 }
 
 // Helper to add items to slice metadata
-func (e *Error) addToSliceMeta(key string, value string) *Error {
-	if e.Meta == nil {
-		e.Meta = make(map[string]any)
-	}
-
-	slice, exists := e.Meta[key]
-	if !exists {
-		e.Meta[key] = []string{value}
-		return e
-	}
-
-	stringSlice := slice.([]string)
-	e.Meta[key] = append(stringSlice, value)
+func (e *Error) addToSliceMeta(key MetaKey[[]string], value string) *Error {
+	slice, _ := getMeta(e, key)
+	setMeta(e, key, append(slice, value))
 	return e
 }
@@ -0,0 +1,277 @@
+package fail
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LocaleLister is implemented by Localizers that can list the locales they hold
+// ExportCatalog uses it to discover locales when none are given
+type LocaleLister interface {
+	Locales() []string
+}
+
+// Catalog is the full description of the errors of a registry, see Registry.ExportCatalog
+type Catalog struct {
+	Registry      string         `json:"registry"`
+	DefaultLocale string         `json:"default_locale,omitempty"`
+	Locales       []string       `json:"locales"`
+	Entries       []CatalogEntry `json:"entries"`
+}
+
+// CatalogEntry describes a registered error
+type CatalogEntry struct {
+	IDListEntry
+
+	Message       string            `json:"message"` // Default message or template
+	System        bool              `json:"system"`
+	Retryable     bool              `json:"retryable"`              // Default MetaRetryable, then the RetryPolicy
+	MaxAttempts   int               `json:"max_attempts,omitempty"` // From the RetryPolicy
+	DefaultArgs   []any             `json:"default_args,omitempty"`
+	Meta          map[string]any    `json:"meta,omitempty"`          // Default meta
+	Localizations map[string]string `json:"localizations,omitempty"` // locale -> template
+}
+
+// ExportCatalog describes every error registered in the global registry
+// See Registry.ExportCatalog
+func ExportCatalog(locales ...string) *Catalog {
+	return global.ExportCatalog(locales...)
+}
+
+// ExportCatalog describes every error registered in this registry: its ID, default
+// message, system flag, default args and meta, retryability and its template in each locale
+//
+// Translations are looked up for the given locales, if none are given the locales come
+// from the Localizer when it implements LocaleLister, from translations still waiting
+// for a Localizer and from the default locale. Render the result with Catalog.JSON,
+// Catalog.Markdown or Catalog.HTML
+//
+// Example:
+//
+//	page, err := fail.ExportCatalog().HTML()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("errors.html", page, 0644)
+func (r *Registry) ExportCatalog(locales ...string) *Catalog {
+	r.mu.RLock()
+	templates := make([]*Error, 0, len(r.errors))
+	for _, tmpl := range r.errors {
+		templates = append(templates, tmpl)
+	}
+	policies := make(map[ErrorID]RetryPolicy, len(r.retryPolicies))
+	for id, p := range r.retryPolicies {
+		policies[id] = p
+	}
+	// Inner maps too, RegisterLocalizations keeps writing them under the lock
+	pending := make(map[ErrorID]map[string]string, len(r.pendingLocalizations))
+	for id, msgs := range r.pendingLocalizations {
+		pending[id] = make(map[string]string, len(msgs))
+		for locale, msg := range msgs {
+			pending[id][locale] = msg
+		}
+	}
+	defaultArgs := make(map[ErrorID][]any, len(r.definitions))
+	for id, def := range r.definitions {
+		defaultArgs[id] = def.DefaultArgs
+	}
+	localizer := r.localization
+	defaultLocale := r.defaultLocale
+	r.mu.RUnlock()
+
+	deprecations := globalIDRegistry.deprecationMap()
+
+	if len(locales) == 0 {
+		locales = catalogLocales(localizer, pending, defaultLocale)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		a, b := templates[i].ID, templates[j].ID
+		if a.domain != b.domain {
+			return a.domain < b.domain
+		}
+		if a.isStatic != b.isStatic {
+			return a.isStatic
+		}
+		return a.number < b.number
+	})
+
+	catalog := &Catalog{
+		Registry:      r.name,
+		DefaultLocale: defaultLocale,
+		Locales:       locales,
+		Entries:       make([]CatalogEntry, 0, len(templates)),
+	}
+
+	for _, tmpl := range templates {
+		id := tmpl.ID
+		entry := CatalogEntry{
+			IDListEntry: IDListEntry{
+				Name:   id.name,
+				Domain: id.domain,
+				Static: id.isStatic,
+				Level:  id.level,
+				Number: id.number,
+				ID:     id.String(),
+			},
+			Message: tmpl.Message,
+			System:  tmpl.IsSystem,
+		}
+		entry.setStatus(deprecations)
+
+		// Definitions own the default args, templates registered without one keep theirs
+		args, hasDefinition := defaultArgs[id]
+		if !hasDefinition {
+			args = tmpl.Args
+		}
+		if len(args) > 0 {
+			entry.DefaultArgs = append([]any(nil), args...)
+		}
+		if len(tmpl.Meta) > 0 {
+			entry.Meta = make(map[string]any, len(tmpl.Meta))
+			for k, v := range tmpl.Meta {
+				entry.Meta[k] = v
+			}
+		}
+
+		policy, hasPolicy := policies[id]
+		if hasPolicy {
+			entry.Retryable = policy.Retryable
+			entry.MaxAttempts = policy.MaxAttempts
+		}
+		if v, ok := getMeta(tmpl, MetaRetryable); ok {
+			entry.Retryable = v
+		}
+
+		for _, locale := range locales {
+			msg := pending[id][locale]
+			if localizer != nil {
+				if localized := localizer.Localize(id, locale); localized != "" {
+					msg = localized
+				}
+			}
+			if msg == "" {
+				continue
+			}
+			if entry.Localizations == nil {
+				entry.Localizations = make(map[string]string, len(locales))
+			}
+			entry.Localizations[locale] = msg
+		}
+
+		catalog.Entries = append(catalog.Entries, entry)
+	}
+
+	return catalog
+}
+
+// catalogLocales collects the known locales, sorted
+func catalogLocales(localizer Localizer, pending map[ErrorID]map[string]string, defaultLocale string) []string {
+	set := make(map[string]struct{})
+	if lister, ok := localizer.(LocaleLister); ok {
+		for _, locale := range lister.Locales() {
+			set[locale] = struct{}{}
+		}
+	}
+	for _, msgs := range pending {
+		for locale := range msgs {
+			set[locale] = struct{}{}
+		}
+	}
+	if defaultLocale != "" {
+		set[defaultLocale] = struct{}{}
+	}
+
+	locales := make([]string, 0, len(set))
+	for locale := range set {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Domains returns the domains of the catalog in entry order
+func (c *Catalog) Domains() []string {
+	var domains []string
+	for i, e := range c.Entries {
+		if i == 0 || c.Entries[i-1].Domain != e.Domain {
+			domains = append(domains, e.Domain)
+		}
+	}
+	return domains
+}
+
+// JSON renders the catalog as indented JSON
+func (c *Catalog) JSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// Markdown renders the catalog as one table per domain
+func (c *Catalog) Markdown() []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Error Catalog (%s)\n", c.Registry)
+	for _, domain := range c.Domains() {
+		fmt.Fprintf(&b, "\n## %s\n\n", domain)
+
+		b.WriteString("| ID | Name | Message | System | Retryable | Meta |")
+		for _, locale := range c.Locales {
+			fmt.Fprintf(&b, " %s |", markdownCell(locale))
+		}
+		b.WriteString("\n|---|---|---|---|---|---|")
+		for range c.Locales {
+			b.WriteString("---|")
+		}
+		b.WriteString("\n")
+
+		for _, e := range c.Entries {
+			if e.Domain != domain {
+				continue
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |",
+				e.ID, markdownCell(e.displayName()), markdownCell(e.Message), yesNo(e.System), yesNo(e.Retryable), markdownCell(e.metaString()))
+			for _, locale := range c.Locales {
+				fmt.Fprintf(&b, " %s |", markdownCell(e.Localizations[locale]))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+// displayName returns the name, with its replacement when deprecated
+func (e CatalogEntry) displayName() string {
+	if e.Status == IDStatusDeprecated {
+		return fmt.Sprintf("%s (deprecated, use %s)", e.Name, e.ReplacedBy)
+	}
+	return e.Name
+}
+
+// metaString formats default meta as sorted "key=value" pairs
+func (e CatalogEntry) metaString() string {
+	keys := make([]string, 0, len(e.Meta))
+	for k := range e.Meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, e.Meta[k])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
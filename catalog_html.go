@@ -0,0 +1,77 @@
+package fail
+
+import (
+	"bytes"
+	"html/template"
+)
+
+// HTML renders the catalog as a self-contained page (inline style and search, no assets)
+func (c *Catalog) HTML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := catalogTemplate.Execute(&buf, c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var catalogTemplate = template.Must(template.New("catalog").Funcs(template.FuncMap{
+	"yesNo": yesNo,
+	"name":  func(e CatalogEntry) string { return e.displayName() },
+	"meta":  func(e CatalogEntry) string { return e.metaString() },
+	"localized": func(e CatalogEntry, locale string) string {
+		return e.Localizations[locale]
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Error Catalog ({{.Registry}})</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+h1 { font-size: 1.5rem; }
+h2 { font-size: 1.2rem; margin-top: 2rem; }
+input { padding: .4rem .6rem; width: 24rem; max-width: 100%; }
+table { border-collapse: collapse; width: 100%; margin-top: .5rem; }
+th, td { border: 1px solid #ddd; padding: .35rem .5rem; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+code { font-size: .9em; }
+.yes { color: #b35900; font-weight: 600; }
+.muted { color: #888; }
+</style>
+</head>
+<body>
+<h1>Error Catalog ({{.Registry}})</h1>
+<p class="muted">{{len .Entries}} errors{{if .DefaultLocale}}, default locale {{.DefaultLocale}}{{end}}</p>
+<input id="search" type="search" placeholder="Filter by ID, name or message">
+{{- $c := .}}
+{{- range $domain := .Domains}}
+<section class="domain">
+<h2>{{$domain}}</h2>
+<table>
+<thead><tr><th>ID</th><th>Name</th><th>Message</th><th>System</th><th>Retryable</th><th>Meta</th>{{range $c.Locales}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range $c.Entries}}{{if eq .Domain $domain}}
+<tr><td><code>{{.ID}}</code></td><td>{{name .}}</td><td>{{.Message}}</td><td{{if .System}} class="yes"{{end}}>{{yesNo .System}}</td><td{{if .Retryable}} class="yes"{{end}}>{{yesNo .Retryable}}</td><td>{{meta .}}</td>{{$e := .}}{{range $c.Locales}}<td>{{localized $e .}}</td>{{end}}</tr>
+{{- end}}{{end}}
+</tbody>
+</table>
+</section>
+{{- end}}
+<script>
+document.getElementById("search").addEventListener("input", function (ev) {
+  var q = ev.target.value.toLowerCase();
+  document.querySelectorAll("section.domain").forEach(function (section) {
+    var shown = 0;
+    section.querySelectorAll("tbody tr").forEach(function (row) {
+      var match = row.textContent.toLowerCase().indexOf(q) >= 0;
+      row.style.display = match ? "" : "none";
+      if (match) shown++;
+    });
+    section.style.display = shown ? "" : "none";
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -32,9 +32,7 @@ func (c *ErrorChain) Then(fn func() error) *ErrorChain {
 		return c
 	}
 	if err := fn(); err != nil {
-		c.err = From(err).MergeMeta(map[string]any{
-			"chain_step_index": c.step,
-		})
+		c.err = SetMeta(From(err), MetaChainStepIndex, c.step)
 	} else {
 		c.step++
 	}
@@ -47,10 +45,7 @@ func (c *ErrorChain) ThenCtx(stepName string, fn func() error) *ErrorChain {
 		return c
 	}
 	if err := fn(); err != nil {
-		c.err = From(err).MergeMeta(map[string]any{
-			"chain_step":       stepName,
-			"chain_step_index": c.step,
-		})
+		c.err = SetMeta(SetMeta(From(err), MetaChainStep, stepName), MetaChainStepIndex, c.step)
 	} else {
 		c.step++
 	}
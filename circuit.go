@@ -0,0 +1,333 @@
+package fail
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker
+type CircuitState int
+
+const (
+	CircuitStateClosed   CircuitState = iota // Calls flow, counted failures are tracked
+	CircuitStateOpen                         // Calls are rejected with a CircuitOpen error
+	CircuitStateHalfOpen                     // A limited number of trial calls are allowed
+)
+
+// String returns the state name (e.g., "closed")
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitStateClosed:
+		return "closed"
+	case CircuitStateOpen:
+		return "open"
+	case CircuitStateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker
+// Zero values are replaced by the documented defaults
+type CircuitBreakerConfig struct {
+	// Name identifies the breaker in errors and hooks (default: "circuit")
+	Name string
+
+	// FailureThreshold is the number of consecutive counted failures that trips the breaker (default: 5)
+	FailureThreshold int
+
+	// OpenTimeout is how long the breaker stays open before allowing trial calls (default: 30s)
+	OpenTimeout time.Duration
+
+	// HalfOpenMaxCalls is the number of concurrent trial calls allowed while half-open (default: 1)
+	HalfOpenMaxCalls int
+
+	// SuccessThreshold is the number of successful trial calls needed to close again (default: 1)
+	SuccessThreshold int
+
+	// Counts decides which errors count toward the threshold (default: CountSystem)
+	// Errors that are not counted are treated as successes, the dependency answered
+	Counts func(error) bool
+
+	// Now is the clock used by the breaker (default: time.Now), inject for deterministic tests
+	Now func() time.Time
+
+	// Registry receives the state change hooks (default: global registry)
+	Registry *Registry
+}
+
+// CountSystem counts only system errors toward the failure threshold
+func CountSystem(err error) bool {
+	return IsSystem(err)
+}
+
+// CountIDs returns a Counts predicate matching only the given IDs
+func CountIDs(ids ...ErrorID) func(error) bool {
+	return func(err error) bool {
+		for _, id := range ids {
+			if Is(err, id) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CountDomains returns a Counts predicate matching only errors whose ID is in one of the given domains
+func CountDomains(domains ...string) func(error) bool {
+	return func(err error) bool {
+		id, ok := GetID(err)
+		if !ok {
+			return false
+		}
+		for _, d := range domains {
+			if id.Domain() == d {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CircuitBreaker stops calling a failing dependency until it had time to recover
+// It trips based on *Error classification, see CircuitBreakerConfig.Counts
+type CircuitBreaker struct {
+	mu     sync.Mutex
+	config CircuitBreakerConfig
+
+	state      CircuitState
+	generation uint64 // incremented on every state change to ignore stale results
+	failures   int
+	successes  int
+	inFlight   int // trial calls running while half-open
+	openedAt   time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+//
+// Example:
+//
+//	cb := fail.NewCircuitBreaker(fail.CircuitBreakerConfig{
+//		Name:             "payments",
+//		FailureThreshold: 3,
+//		OpenTimeout:      10 * time.Second,
+//		Counts:           fail.CountDomains("PAYMENT"),
+//	})
+//
+//	err := cb.Execute(func() error {
+//		return payments.Charge(order)
+//	})
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.Name == "" {
+		config.Name = "circuit"
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.HalfOpenMaxCalls <= 0 {
+		config.HalfOpenMaxCalls = 1
+	}
+	if config.SuccessThreshold <= 0 {
+		config.SuccessThreshold = 1
+	}
+	if config.Counts == nil {
+		config.Counts = CountSystem
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	if config.Registry == nil {
+		config.Registry = global
+	}
+
+	return &CircuitBreaker{config: config}
+}
+
+// Name returns the breaker name
+func (cb *CircuitBreaker) Name() string {
+	return cb.config.Name
+}
+
+// State returns the current state, moving from open to half-open if OpenTimeout elapsed
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	from, to, changed := cb.refresh()
+	state := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(from, to, changed)
+	return state
+}
+
+// Reset forces the breaker back to closed and clears its counters
+func (cb *CircuitBreaker) Reset() {
+	cb.mu.Lock()
+	from, to, changed := cb.setState(CircuitStateClosed)
+	cb.mu.Unlock()
+
+	cb.notify(from, to, changed)
+}
+
+// Execute calls fn if the breaker allows it and records the outcome
+// Returns a CircuitOpen error without calling fn while the breaker is open
+// or while all half-open trial slots are in use. A panic in fn is recorded as a
+// failure before it propagates
+func (cb *CircuitBreaker) Execute(fn func() error) error {
+	gen, err := cb.allow()
+	if err != nil {
+		return err
+	}
+
+	returned := false
+	defer cb.recordPanic(gen, &returned)
+
+	err = fn()
+	returned = true
+	cb.record(gen, err)
+	return err
+}
+
+// ExecuteValue is the (T, error) version of CircuitBreaker.Execute
+func ExecuteValue[T any](cb *CircuitBreaker, fn func() (T, error)) (T, error) {
+	var zero T
+
+	gen, err := cb.allow()
+	if err != nil {
+		return zero, err
+	}
+
+	returned := false
+	defer cb.recordPanic(gen, &returned)
+
+	v, err := fn()
+	returned = true
+	cb.record(gen, err)
+	if err != nil {
+		return zero, err
+	}
+	return v, nil
+}
+
+func (cb *CircuitBreaker) allow() (uint64, error) {
+	cb.mu.Lock()
+	from, to, changed := cb.refresh()
+
+	var rejected *Error
+	switch cb.state {
+	case CircuitStateOpen:
+		remaining := cb.config.OpenTimeout - cb.config.Now().Sub(cb.openedAt)
+		rejected = cb.openError(remaining)
+	case CircuitStateHalfOpen:
+		if cb.inFlight >= cb.config.HalfOpenMaxCalls {
+			rejected = cb.openError(0)
+		} else {
+			cb.inFlight++
+		}
+	}
+	gen := cb.generation
+	cb.mu.Unlock()
+
+	cb.notify(from, to, changed)
+	if rejected != nil {
+		return 0, rejected
+	}
+	return gen, nil
+}
+
+// recordPanic records a call that didn't return (panic or runtime.Goexit) as a failure,
+// so a half-open trial slot is never leaked. Deferred, the panic keeps unwinding
+func (cb *CircuitBreaker) recordPanic(gen uint64, returned *bool) {
+	if !*returned {
+		cb.recordCounted(gen, true)
+	}
+}
+
+func (cb *CircuitBreaker) record(gen uint64, err error) {
+	cb.recordCounted(gen, err != nil && cb.config.Counts(err))
+}
+
+// recordCounted records the outcome of a call, counted is true for failures
+func (cb *CircuitBreaker) recordCounted(gen uint64, counted bool) {
+	cb.mu.Lock()
+	// Result of a call admitted before the last state change, ignore it
+	if gen != cb.generation {
+		cb.mu.Unlock()
+		return
+	}
+
+	var from, to CircuitState
+	var changed bool
+
+	switch cb.state {
+	case CircuitStateClosed:
+		if counted {
+			cb.failures++
+			if cb.failures >= cb.config.FailureThreshold {
+				from, to, changed = cb.setState(CircuitStateOpen)
+			}
+		} else {
+			cb.failures = 0
+		}
+	case CircuitStateHalfOpen:
+		cb.inFlight--
+		if counted {
+			from, to, changed = cb.setState(CircuitStateOpen)
+		} else {
+			cb.successes++
+			if cb.successes >= cb.config.SuccessThreshold {
+				from, to, changed = cb.setState(CircuitStateClosed)
+			}
+		}
+	}
+	cb.mu.Unlock()
+
+	cb.notify(from, to, changed)
+}
+
+// refresh moves an open breaker to half-open once OpenTimeout elapsed, must hold cb.mu
+func (cb *CircuitBreaker) refresh() (CircuitState, CircuitState, bool) {
+	if cb.state == CircuitStateOpen && cb.config.Now().Sub(cb.openedAt) >= cb.config.OpenTimeout {
+		return cb.setState(CircuitStateHalfOpen)
+	}
+	return cb.state, cb.state, false
+}
+
+// setState changes state and resets counters, must hold cb.mu
+func (cb *CircuitBreaker) setState(to CircuitState) (CircuitState, CircuitState, bool) {
+	from := cb.state
+	cb.failures = 0
+	cb.successes = 0
+	cb.inFlight = 0
+	if from == to {
+		return from, to, false
+	}
+
+	cb.state = to
+	cb.generation++
+	if to == CircuitStateOpen {
+		cb.openedAt = cb.config.Now()
+	}
+	return from, to, true
+}
+
+// notify runs state change hooks outside the lock
+func (cb *CircuitBreaker) notify(from, to CircuitState, changed bool) {
+	if !changed {
+		return
+	}
+	cb.config.Registry.hooks.runCircuitStateChange(cb.config.Name, from, to)
+}
+
+func (cb *CircuitBreaker) openError(retryAfter time.Duration) *Error {
+	err := New(CircuitOpen).WithArgs(cb.config.Name)
+	setMeta(err, MetaCircuit, cb.config.Name)
+	setMeta(err, MetaCircuitState, cb.state.String())
+	if retryAfter > 0 {
+		_ = err.RetryAfter(retryAfter)
+	}
+	return err.Render()
+}
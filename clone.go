@@ -0,0 +1,110 @@
+package fail
+
+// Cloner lets user types stored in Meta or Args be copied by DeepClone
+// Clone must return a value of the same type that shares no mutable state
+type Cloner interface {
+	Clone() any
+}
+
+// DeepClone returns a copy of the error that shares no mutable state with it
+//
+// Unlike Clone, Args and Meta values are copied too:
+//   - Values implementing Cloner are copied with their Clone method
+//   - []ValidationError, []string, []any, []Frame, RetryHistory, map[string]any and
+//     map[string]string are copied, nested []any and map[string]any values recursively
+//   - *Error values are deep cloned, also inside []*Error and map[string]*Error
+//   - Any other value is shared, treat it as immutable
+//
+// If cloneCause is true and the cause is a *Error it is deep cloned as well, recursively.
+// Other causes are always shared
+//
+// Example:
+//
+//	copy := err.DeepClone(false).Validation("email", "required") // err is untouched
+func (e *Error) DeepClone(cloneCause bool) *Error {
+	return e.deepClone(cloneCause, 0)
+}
+
+func (e *Error) deepClone(cloneCause bool, depth int) *Error {
+	if e == nil {
+		return nil
+	}
+
+	clone := *e
+	clone.shared = false
+	clone.owned = false
+
+	if e.Args != nil {
+		clone.Args = make([]any, len(e.Args))
+		for i, arg := range e.Args {
+			clone.Args[i] = cloneValue(arg, depth)
+		}
+	}
+
+	if e.Meta != nil {
+		clone.Meta = make(map[string]any, len(e.Meta))
+		for k, v := range e.Meta {
+			clone.Meta[k] = cloneValue(v, depth)
+		}
+	}
+
+	if cause, ok := e.Cause.(*Error); ok && cloneCause && depth < maxCauseDepth {
+		clone.Cause = cause.deepClone(true, depth+1)
+	}
+
+	return &clone
+}
+
+// cloneValue copies the known mutable types, depth guards against cycles
+func cloneValue(v any, depth int) any {
+	if depth > maxCauseDepth {
+		return v
+	}
+
+	switch v := v.(type) {
+	case Cloner:
+		return v.Clone()
+	case *Error:
+		return v.deepClone(false, depth+1)
+	case []*Error: // ErrorGroup.ToError "errors"
+		out := make([]*Error, len(v))
+		for i, item := range v {
+			out[i] = item.deepClone(false, depth+1)
+		}
+		return out
+	case map[string]*Error: // RegisterMany "failures"
+		out := make(map[string]*Error, len(v))
+		for k, item := range v {
+			out[k] = item.deepClone(false, depth+1)
+		}
+		return out
+	case []ValidationError:
+		return append([]ValidationError(nil), v...)
+	case []string:
+		return append([]string(nil), v...)
+	case []Frame:
+		return append([]Frame(nil), v...)
+	case RetryHistory:
+		return append(RetryHistory(nil), v...)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = cloneValue(item, depth+1)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = cloneValue(item, depth+1)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for k, item := range v {
+			out[k] = item
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,89 @@
+// Command failcompat compares two fail.ExportIDList catalogs and fails on breaking changes
+//
+// Usage:
+//
+//	failcompat [-json] [-quiet] [-allow-renames] old.json new.json
+//
+// Export the catalog on every release and compare it in CI:
+//
+//	go run github.com/MintzyG/fail/v3/cmd/failcompat@latest errors-v1.json errors.json
+//
+// Renames break code using the Go identifier, LookupByName and the exported "name", so they
+// are breaking. -allow-renames reports them as non-breaking for consumers that only persist
+// ID strings.
+//
+// Exit status is 0 when compatible, 1 on breaking changes and 2 on usage or input errors
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	quiet := flag.Bool("quiet", false, "only print breaking changes")
+	allowRenames := flag.Bool("allow-renames", false, "report renames (same ID, new name) as non-breaking")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: failcompat [-json] [-quiet] [-allow-renames] old.json new.json")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	report, err := compare(flag.Arg(0), flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failcompat:", err)
+		os.Exit(2)
+	}
+
+	if *allowRenames {
+		for i := range report.Changes {
+			if report.Changes[i].Kind == fail.CompatRenamed {
+				report.Changes[i].Breaking = false
+			}
+		}
+	}
+
+	changes := report.Changes
+	if *quiet {
+		changes = report.BreakingChanges()
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(fail.CompatReport{Changes: changes})
+	} else {
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+		if !report.Breaking() && !*quiet {
+			fmt.Printf("compatible: %d change(s), none breaking\n", len(changes))
+		}
+	}
+
+	if report.Breaking() {
+		os.Exit(1)
+	}
+}
+
+func compare(oldPath, newPath string) (*fail.CompatReport, error) {
+	oldList, err := os.ReadFile(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newList, err := os.ReadFile(newPath)
+	if err != nil {
+		return nil, err
+	}
+	return fail.CompareIDLists(oldList, newList)
+}
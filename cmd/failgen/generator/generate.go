@@ -0,0 +1,260 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// failImport is the import path of the library in generated files
+const failImport = "github.com/MintzyG/fail/v3"
+
+// Options configures Generate
+type Options struct {
+	Package string // Overrides Spec.Package
+	Source  string // Spec file name, mentioned in the generated header
+}
+
+// Generate assigns numbers through lock and returns the formatted Go file for spec
+// lock is updated in place, save it after a successful generation
+func Generate(spec *Spec, lock *Lock, opts Options) ([]byte, error) {
+	pkg := spec.Package
+	if opts.Package != "" {
+		pkg = opts.Package
+	}
+	if pkg == "" {
+		return nil, fmt.Errorf("no package name, set it in the spec or with -pkg")
+	}
+
+	if err := lock.assign(spec); err != nil {
+		return nil, err
+	}
+	if err := checkSimilarNames(lock.IDs); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]resolvedError)
+	for _, e := range spec.errors() {
+		byName[e.Name] = e
+	}
+
+	var b bytes.Buffer
+	source := ""
+	if opts.Source != "" {
+		source = " from " + opts.Source
+	}
+	fmt.Fprintf(&b, "// Code generated by failgen%s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import %q\n\n", failImport)
+
+	writeIDs(&b, spec, lock)
+
+	errs := spec.errors()
+	if len(errs) > 0 {
+		if spec.UseSentinels() {
+			writeSentinels(&b, errs)
+		} else {
+			writeRegisterMany(&b, errs)
+		}
+		writeLocalizations(&b, errs, spec.UseSentinels())
+	}
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return out, nil
+}
+
+// writeIDs emits the fail.ID block, grouped by domain and sorted by number so the
+// global registry sees each sequence in order
+func writeIDs(b *bytes.Buffer, spec *Spec, lock *Lock) {
+	var domains []string
+	known := make(map[string]bool)
+	for _, d := range spec.Domains {
+		domains = append(domains, d.Name)
+		known[d.Name] = true
+	}
+	var orphans []string // domains only left in the lock
+	for _, id := range lock.IDs {
+		if !known[id.Domain] {
+			known[id.Domain] = true
+			orphans = append(orphans, id.Domain)
+		}
+	}
+	sort.Strings(orphans)
+	domains = append(domains, orphans...)
+
+	b.WriteString("// Error IDs, numbers are pinned by the lock file\nvar (\n")
+	for i, domain := range domains {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "\t// %s\n", domain)
+		for _, id := range lock.IDs {
+			if id.Domain != domain {
+				continue
+			}
+			if id.Removed {
+				fmt.Fprintf(b, "\n\t// Deprecated: %s was removed from the spec, the ID is kept to reserve its number\n", id.Name)
+			}
+			fmt.Fprintf(b, "\t%s = fail.ID(%d, %q, %d, %v, %q) // %s\n",
+				id.Name, id.Level, id.Domain, id.Number, id.Static, id.Name, id)
+		}
+	}
+	b.WriteString(")\n\n")
+}
+
+func writeSentinels(b *bytes.Buffer, errs []resolvedError) {
+	b.WriteString("// Sentinels\nvar (\n")
+	for _, e := range errs {
+		fmt.Fprintf(b, "\t%s = fail.Form(%s, %q, %v, %s)\n",
+			sentinelName(e.Name), e.Name, e.Message, e.System, metaLiteral(e.Meta))
+	}
+	b.WriteString(")\n\n")
+}
+
+func writeRegisterMany(b *bytes.Buffer, errs []resolvedError) {
+	b.WriteString("var _ = fail.RegisterMany(\n")
+	for _, e := range errs {
+		fmt.Fprintf(b, "\t&fail.ErrorDefinition{ID: %s, DefaultMessage: %q, IsSystem: %v",
+			e.Name, e.Message, e.System)
+		if len(e.Meta) > 0 {
+			fmt.Fprintf(b, ", Meta: %s", metaLiteral(e.Meta))
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString(")\n\n")
+}
+
+func writeLocalizations(b *bytes.Buffer, errs []resolvedError, sentinels bool) {
+	var body bytes.Buffer
+	for _, e := range errs {
+		if len(e.Localizations) == 0 {
+			continue
+		}
+		target := fmt.Sprintf("fail.New(%s)", e.Name)
+		if sentinels {
+			target = sentinelName(e.Name)
+		}
+		fmt.Fprintf(&body, "\t%s.AddLocalizations(map[string]string{\n", target)
+		for _, locale := range sortedKeys(e.Localizations) {
+			fmt.Fprintf(&body, "\t\t%q: %q,\n", locale, e.Localizations[locale])
+		}
+		body.WriteString("\t})\n")
+	}
+	if body.Len() == 0 {
+		return
+	}
+	b.WriteString("func init() {\n")
+	b.Write(body.Bytes())
+	b.WriteString("}\n")
+}
+
+// sentinelName returns the variable of the Form sentinel of an error (e.g., ErrAuthInvalidCredentials)
+func sentinelName(name string) string {
+	return "Err" + name
+}
+
+func metaLiteral(meta map[string]any) string {
+	if len(meta) == 0 {
+		return "nil"
+	}
+	var b strings.Builder
+	b.WriteString("map[string]any{")
+	for i, k := range sortedKeys(meta) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		v, _ := literal(meta[k]) // checked by Spec.Validate
+		fmt.Fprintf(&b, "%q: %s", k, v)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// literal returns the Go literal of a scalar decoded from YAML or JSON
+func literal(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "nil", nil
+	case string:
+		return strconv.Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return floatLiteral(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return strconv.FormatInt(i, 10), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return "", err
+		}
+		return floatLiteral(f), nil
+	}
+	return "", fmt.Errorf("unsupported value %v (%T), only scalars are allowed", v, v)
+}
+
+// floatLiteral keeps a decimal point so the value stays a float64 in map[string]any
+func floatLiteral(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEnN") {
+		s += ".0"
+	}
+	return s
+}
+
+// checkSimilarNames mirrors the distance check of fail.ID, removed names still count
+func checkSimilarNames(ids []LockedID) error {
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			if d := levenshteinDistance(ids[i].Name, ids[j].Name); d <= 3 {
+				return fmt.Errorf("error name %q is too similar to %q (distance: %d, must be > 3)",
+					ids[j].Name, ids[i].Name, d)
+			}
+		}
+	}
+	return nil
+}
+
+func levenshteinDistance(s1, s2 string) int {
+	m, n := len(s1), len(s2)
+	prev := make([]int, n+1)
+	curr := make([]int, n+1)
+	for j := 0; j <= n; j++ {
+		prev[j] = j
+	}
+	for i := 1; i <= m; i++ {
+		curr[0] = i
+		for j := 1; j <= n; j++ {
+			cost := 1
+			if s1[i-1] == s2[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[n]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
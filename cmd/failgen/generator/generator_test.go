@@ -0,0 +1,255 @@
+package generator_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MintzyG/fail/v3/cmd/failgen/generator"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func generate(t *testing.T, spec *generator.Spec, lock *generator.Lock) string {
+	t.Helper()
+	code, err := generator.Generate(spec, lock, generator.Options{Source: "catalog.yaml"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	return string(code)
+}
+
+func loadSpec(t *testing.T, name string) *generator.Spec {
+	t.Helper()
+	spec, err := generator.LoadSpec(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("LoadSpec: %v", err)
+	}
+	return spec
+}
+
+func TestGenerate_Golden(t *testing.T) {
+	lock := &generator.Lock{}
+	got := generate(t, loadSpec(t, "catalog.yaml"), lock)
+
+	golden := filepath.Join("testdata", "catalog_gen.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("generated code differs from %s (run with -update)\n%s", golden, got)
+	}
+}
+
+func TestGenerate_JSONMatchesYAML(t *testing.T) {
+	fromYAML := generate(t, loadSpec(t, "catalog.yaml"), &generator.Lock{})
+	fromJSON := generate(t, loadSpec(t, "catalog.json"), &generator.Lock{})
+	if fromYAML != fromJSON {
+		t.Errorf("JSON and YAML specs should generate the same code\nyaml:\n%s\njson:\n%s", fromYAML, fromJSON)
+	}
+}
+
+func TestGenerate_LockKeepsNumbers(t *testing.T) {
+	spec := loadSpec(t, "catalog.yaml")
+	lock := &generator.Lock{}
+	generate(t, spec, lock)
+
+	// A new error declared first must not shift the locked ones
+	auth := &spec.Domains[0]
+	auth.Errors = append([]generator.ErrorSpec{{Name: "AuthAccountLocked", Static: true, Message: "locked"}}, auth.Errors...)
+	code := generate(t, spec, lock)
+
+	for _, want := range []string{
+		`AuthInvalidCredentials = fail.ID(0, "AUTH", 0, true, "AuthInvalidCredentials")`,
+		`AuthTokenExpired       = fail.ID(0, "AUTH", 1, true, "AuthTokenExpired")`,
+		`AuthAccountLocked      = fail.ID(0, "AUTH", 2, true, "AuthAccountLocked")`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected %q in\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerate_RemovedErrorKeepsNumber(t *testing.T) {
+	spec := loadSpec(t, "catalog.yaml")
+	lock := &generator.Lock{}
+	generate(t, spec, lock)
+
+	auth := &spec.Domains[0]
+	auth.Errors = auth.Errors[1:] // drop AuthInvalidCredentials
+	code := generate(t, spec, lock)
+
+	if !strings.Contains(code, "// Deprecated: AuthInvalidCredentials was removed from the spec") {
+		t.Errorf("removed error should stay as a deprecated ID\n%s", code)
+	}
+	if strings.Contains(code, "ErrAuthInvalidCredentials") {
+		t.Error("removed error should not get a sentinel")
+	}
+
+	// Adding an error afterwards must not reuse the reserved number
+	auth.Errors = append(auth.Errors, generator.ErrorSpec{Name: "AuthSessionRevoked", Static: true})
+	code = generate(t, spec, lock)
+	if !strings.Contains(code, `fail.ID(0, "AUTH", 2, true, "AuthSessionRevoked")`) {
+		t.Errorf("new error should take the next free number\n%s", code)
+	}
+
+	// Bringing it back restores it
+	auth.Errors = append(auth.Errors, generator.ErrorSpec{Name: "AuthInvalidCredentials", Static: true})
+	code = generate(t, spec, lock)
+	if strings.Contains(code, "Deprecated") {
+		t.Errorf("restored error should not be deprecated\n%s", code)
+	}
+}
+
+func TestLock_SaveLoad(t *testing.T) {
+	lock := &generator.Lock{}
+	generate(t, loadSpec(t, "catalog.yaml"), lock)
+
+	path := filepath.Join(t.TempDir(), "errors.lock.json")
+	if err := lock.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := generator.LoadLock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.IDs) != 4 {
+		t.Fatalf("expected 4 locked IDs, got %d", len(loaded.IDs))
+	}
+	if got := loaded.IDs[0].String(); got != "0_AUTH_0000_S" {
+		t.Errorf("expected lock sorted by domain, type and number, first is %s", got)
+	}
+
+	missing, err := generator.LoadLock(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || len(missing.IDs) != 0 {
+		t.Errorf("missing lock should be empty, got %v, %v", missing, err)
+	}
+}
+
+func TestGenerate_RegisterMany(t *testing.T) {
+	spec := loadSpec(t, "catalog.yaml")
+	off := false
+	spec.Sentinels = &off
+	code := generate(t, spec, &generator.Lock{})
+
+	for _, want := range []string{
+		"var _ = fail.RegisterMany(",
+		`&fail.ErrorDefinition{ID: AuthProviderDown, DefaultMessage: "auth provider %s unavailable", IsSystem: true, Meta: map[string]any{"attempts": 3, "retryable": true}},`,
+		"fail.New(AuthInvalidCredentials).AddLocalizations(",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected %q in\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "fail.Form(") {
+		t.Error("RegisterMany mode should not emit sentinels")
+	}
+}
+
+func TestGenerate_Rejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(spec *generator.Spec)
+		want   string
+	}{
+		{
+			name:   "static flip",
+			change: func(s *generator.Spec) { s.Domains[0].Errors[0].Static = false },
+			want:   "flipping it would renumber it",
+		},
+		{
+			name: "domain move",
+			change: func(s *generator.Spec) {
+				s.Domains[1].Errors = append(s.Domains[1].Errors, generator.ErrorSpec{Name: "AuthTokenExpired", Static: true})
+				s.Domains[0].Errors = s.Domains[0].Errors[:1]
+			},
+			want: "moving it to USER would renumber it",
+		},
+		{
+			name:   "level change",
+			change: func(s *generator.Spec) { level := 3; s.Domains[0].Errors[0].Level = &level },
+			want:   "changing it to 3 would change its ID",
+		},
+		{
+			name: "similar names",
+			change: func(s *generator.Spec) {
+				s.Domains[0].Errors = append(s.Domains[0].Errors, generator.ErrorSpec{Name: "AuthTokenExpire"})
+			},
+			want: "too similar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := loadSpec(t, "catalog.yaml")
+			lock := &generator.Lock{}
+			generate(t, spec, lock)
+
+			tt.change(spec)
+			_, err := generator.Generate(spec, lock, generator.Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec generator.Spec
+		want string
+	}{
+		{
+			name: "reserved domain",
+			spec: generator.Spec{Domains: []generator.DomainSpec{{Name: "FAIL"}}},
+			want: "reserved",
+		},
+		{
+			name: "name prefix",
+			spec: generator.Spec{Domains: []generator.DomainSpec{{Name: "AUTH", Errors: []generator.ErrorSpec{{Name: "UserNotFound"}}}}},
+			want: "must start with domain",
+		},
+		{
+			name: "duplicate name",
+			spec: generator.Spec{Domains: []generator.DomainSpec{{Name: "AUTH", Errors: []generator.ErrorSpec{
+				{Name: "AuthExpired"}, {Name: "AuthExpired", Static: true},
+			}}}},
+			want: "declared twice",
+		},
+		{
+			name: "nested meta",
+			spec: generator.Spec{Domains: []generator.DomainSpec{{Name: "AUTH", Errors: []generator.ErrorSpec{
+				{Name: "AuthExpired", Meta: map[string]any{"tags": []any{"a"}}},
+			}}}},
+			want: "only scalars",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadSpec_UnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.yaml")
+	if err := os.WriteFile(path, []byte("package: errs\ndomain: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generator.LoadSpec(path); err == nil {
+		t.Error("expected unknown field to be rejected")
+	}
+}
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// LockVersion is the version of the lock file format
+const LockVersion = 1
+
+// Lock pins the number of every error ever generated, commit it next to the spec
+// Numbers are never reused: errors removed from the spec stay in the lock, and in the
+// generated file as deprecated IDs, so the global registry never sees a gap
+type Lock struct {
+	Version int        `json:"version"`
+	IDs     []LockedID `json:"ids"`
+}
+
+// LockedID is the pinned identity of an error
+type LockedID struct {
+	Name    string `json:"name"`
+	Domain  string `json:"domain"`
+	Static  bool   `json:"static"`
+	Number  int    `json:"number"`
+	Level   int    `json:"level"`
+	Removed bool   `json:"removed,omitempty"` // No longer in the spec, the number stays reserved
+}
+
+// String returns the fail.ErrorID form (e.g., "0_AUTH_0003_S")
+func (l LockedID) String() string {
+	kind := "D"
+	if l.Static {
+		kind = "S"
+	}
+	return fmt.Sprintf("%d_%s_%04d_%s", l.Level, l.Domain, l.Number, kind)
+}
+
+// LoadLock reads a lock file, a missing file yields an empty lock
+func LoadLock(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Lock{Version: LockVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if lock.Version != LockVersion {
+		return nil, fmt.Errorf("%s: unsupported lock version %d", path, lock.Version)
+	}
+	return &lock, nil
+}
+
+// Save writes the lock file in a stable order
+func (l *Lock) Save(path string) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Marshal returns the lock file content, sorted by domain, type and number
+func (l *Lock) Marshal() ([]byte, error) {
+	l.sort()
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func (l *Lock) sort() {
+	sort.SliceStable(l.IDs, func(i, j int) bool {
+		a, b := l.IDs[i], l.IDs[j]
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		if a.Static != b.Static {
+			return a.Static
+		}
+		return a.Number < b.Number
+	})
+}
+
+// assign pins a number to every error of the spec and marks missing ones as removed
+// Locked errors keep their number, new ones take the next free number of their group
+// Changing the domain, static flag or level of a locked error is refused, each one
+// changes its ID string
+func (l *Lock) assign(spec *Spec) error {
+	if l.Version == 0 {
+		l.Version = LockVersion
+	}
+
+	index := make(map[string]int, len(l.IDs))
+	next := make(map[string]int)
+	for i, id := range l.IDs {
+		if _, dup := index[id.Name]; dup {
+			return fmt.Errorf("lock: %q pinned twice", id.Name)
+		}
+		index[id.Name] = i
+		if g := group(id.Domain, id.Static); id.Number >= next[g] {
+			next[g] = id.Number + 1
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range spec.errors() {
+		seen[e.Name] = true
+
+		i, locked := index[e.Name]
+		if !locked {
+			g := group(e.Domain, e.Static)
+			l.IDs = append(l.IDs, LockedID{
+				Name:   e.Name,
+				Domain: e.Domain,
+				Static: e.Static,
+				Number: next[g],
+				Level:  e.Level,
+			})
+			next[g]++
+			continue
+		}
+
+		id := &l.IDs[i]
+		if id.Domain != e.Domain {
+			return fmt.Errorf("%s is locked in domain %s (%s), moving it to %s would renumber it; "+
+				"declare a new error instead", e.Name, id.Domain, id, e.Domain)
+		}
+		if id.Static != e.Static {
+			return fmt.Errorf("%s is locked as static=%v (%s), flipping it would renumber it; "+
+				"declare a new error instead", e.Name, id.Static, id)
+		}
+		if id.Level != e.Level {
+			return fmt.Errorf("%s is locked at level %d (%s), changing it to %d would change its ID; "+
+				"declare a new error instead", e.Name, id.Level, id, e.Level)
+		}
+		id.Removed = false
+	}
+
+	for i := range l.IDs {
+		if !seen[l.IDs[i].Name] {
+			l.IDs[i].Removed = true
+		}
+	}
+
+	l.sort()
+	return checkGaps(l.IDs)
+}
+
+// checkGaps catches hand-edited lock files the global registry would reject
+func checkGaps(ids []LockedID) error {
+	expected := make(map[string]int)
+	for _, id := range ids {
+		g := group(id.Domain, id.Static)
+		if id.Number != expected[g] {
+			return fmt.Errorf("lock: %s has number %d, expected %d in %s (static=%v)",
+				id.Name, id.Number, expected[g], id.Domain, id.Static)
+		}
+		expected[g]++
+	}
+	return nil
+}
+
+func group(domain string, static bool) string {
+	return fmt.Sprintf("%s:%v", domain, static)
+}
@@ -0,0 +1,163 @@
+// Package generator builds a fail.ID catalog from a declarative spec
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reservedDomain mirrors the domain reserved for the library internal IDs
+const reservedDomain = "FAIL"
+
+// Spec is the declarative description of an error catalog
+//
+// Example (YAML):
+//
+//	package: errs
+//	domains:
+//	  - name: AUTH
+//	    level: 0
+//	    errors:
+//	      - name: AuthInvalidCredentials
+//	        static: true
+//	        message: invalid credentials
+//	        localizations:
+//	          pt-BR: credenciais inválidas
+//	      - name: AuthUpstreamDown
+//	        level: 2
+//	        system: true
+//	        message: "auth provider %s unavailable"
+//	        meta:
+//	          retryable: true
+type Spec struct {
+	// Package of the generated file, defaults to $GOPACKAGE when run by go generate
+	Package string `yaml:"package" json:"package"`
+
+	// Sentinels controls the registration style (default true):
+	// true emits one fail.Form sentinel per error, false a single fail.RegisterMany call
+	Sentinels *bool `yaml:"sentinels" json:"sentinels"`
+
+	Domains []DomainSpec `yaml:"domains" json:"domains"`
+}
+
+// DomainSpec groups the errors of a domain
+type DomainSpec struct {
+	Name   string      `yaml:"name" json:"name"`
+	Level  int         `yaml:"level" json:"level"` // Default level of the domain errors
+	Errors []ErrorSpec `yaml:"errors" json:"errors"`
+}
+
+// ErrorSpec describes a single error, its number is assigned by the lock file
+type ErrorSpec struct {
+	Name          string            `yaml:"name" json:"name"`
+	Level         *int              `yaml:"level" json:"level"` // Overrides the domain level
+	Static        bool              `yaml:"static" json:"static"`
+	Message       string            `yaml:"message" json:"message"`
+	System        bool              `yaml:"system" json:"system"`
+	Meta          map[string]any    `yaml:"meta" json:"meta"` // Scalar values only
+	Localizations map[string]string `yaml:"localizations" json:"localizations"`
+}
+
+// UseSentinels reports whether errors are registered through fail.Form
+func (s *Spec) UseSentinels() bool {
+	return s.Sentinels == nil || *s.Sentinels
+}
+
+// LoadSpec reads a spec file, ".json" files are decoded as JSON and anything else as YAML
+// Unknown fields are rejected in both formats
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec Spec
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		dec.UseNumber()
+		err = dec.Decode(&spec)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// Validate checks the rules fail.ID enforces at init time, so a bad spec fails at generation
+func (s *Spec) Validate() error {
+	if s.Package != "" && !token.IsIdentifier(s.Package) {
+		return fmt.Errorf("invalid package name %q", s.Package)
+	}
+
+	domains := make(map[string]bool)
+	names := make(map[string]string) // name -> domain
+	for _, d := range s.Domains {
+		if d.Name == "" {
+			return fmt.Errorf("domain without name")
+		}
+		if d.Name == reservedDomain {
+			return fmt.Errorf("domain %q is reserved for internal errors", reservedDomain)
+		}
+		if domains[d.Name] {
+			return fmt.Errorf("domain %q declared twice", d.Name)
+		}
+		domains[d.Name] = true
+
+		for _, e := range d.Errors {
+			if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+				return fmt.Errorf("error name %q in %s must be an exported Go identifier", e.Name, d.Name)
+			}
+			if !strings.HasPrefix(strings.ToLower(e.Name), strings.ToLower(d.Name)) {
+				return fmt.Errorf("error name %q must start with domain %q", e.Name, d.Name)
+			}
+			if domain, exists := names[e.Name]; exists {
+				return fmt.Errorf("error name %q declared twice (%s and %s)", e.Name, domain, d.Name)
+			}
+			names[e.Name] = d.Name
+
+			for k, v := range e.Meta {
+				if _, err := literal(v); err != nil {
+					return fmt.Errorf("%s: meta %q: %w", e.Name, k, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// errors returns every error of the spec with its resolved level
+func (s *Spec) errors() []resolvedError {
+	var out []resolvedError
+	for _, d := range s.Domains {
+		for _, e := range d.Errors {
+			level := d.Level
+			if e.Level != nil {
+				level = *e.Level
+			}
+			out = append(out, resolvedError{ErrorSpec: e, Domain: d.Name, Level: level})
+		}
+	}
+	return out
+}
+
+type resolvedError struct {
+	ErrorSpec
+	Domain string
+	Level  int
+}
@@ -0,0 +1,5 @@
+module github.com/MintzyG/fail/v3/cmd/failgen
+
+go 1.24.0
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,91 @@
+// Command failgen generates a fail.ID catalog from a declarative YAML or JSON spec
+//
+// Numbers are assigned by failgen and pinned in a lock file, commit it next to the
+// spec so numbers stay stable across versions.
+//
+// Usage, in the package that owns the catalog:
+//
+//	//go:generate go run github.com/MintzyG/fail/v3/cmd/failgen@latest -spec errors.yaml
+//
+// Flags:
+//
+//	-spec  spec file (default "errors.yaml"), ".json" files are read as JSON
+//	-out   generated file (default "<spec>_gen.go")
+//	-lock  lock file (default "<spec>.lock.json")
+//	-pkg   package name (default: spec "package", then $GOPACKAGE)
+//	-check fail if the generated file or the lock file are out of date, for CI
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MintzyG/fail/v3/cmd/failgen/generator"
+)
+
+func main() {
+	specPath := flag.String("spec", "errors.yaml", "spec file (YAML, or JSON with a .json extension)")
+	outPath := flag.String("out", "", "generated Go file (default <spec>_gen.go)")
+	lockPath := flag.String("lock", "", "lock file (default <spec>.lock.json)")
+	pkg := flag.String("pkg", "", "package name (default: spec package, then $GOPACKAGE)")
+	check := flag.Bool("check", false, "fail if the generated files are out of date instead of writing them")
+	flag.Parse()
+
+	base := strings.TrimSuffix(*specPath, filepath.Ext(*specPath))
+	if *outPath == "" {
+		*outPath = base + "_gen.go"
+	}
+	if *lockPath == "" {
+		*lockPath = base + ".lock.json"
+	}
+
+	if err := run(*specPath, *outPath, *lockPath, *pkg, *check); err != nil {
+		fmt.Fprintln(os.Stderr, "failgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, outPath, lockPath, pkg string, check bool) error {
+	spec, err := generator.LoadSpec(specPath)
+	if err != nil {
+		return err
+	}
+	lock, err := generator.LoadLock(lockPath)
+	if err != nil {
+		return err
+	}
+
+	if pkg == "" && spec.Package == "" {
+		pkg = os.Getenv("GOPACKAGE")
+	}
+
+	code, err := generator.Generate(spec, lock, generator.Options{
+		Package: pkg,
+		Source:  filepath.Base(specPath),
+	})
+	if err != nil {
+		return err
+	}
+	lockData, err := lock.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if check {
+		for path, want := range map[string][]byte{outPath: code, lockPath: lockData} {
+			if got, _ := os.ReadFile(path); !bytes.Equal(got, want) {
+				return fmt.Errorf("%s is out of date, run go generate", path)
+			}
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(lockPath, lockData, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, code, 0o644)
+}
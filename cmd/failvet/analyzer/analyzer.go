@@ -0,0 +1,342 @@
+// Package analyzer implements failvet, static checks for github.com/MintzyG/fail/v3
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// failPkg is the import path of the library
+const failPkg = "github.com/MintzyG/fail/v3"
+
+// reservedDomain mirrors the domain reserved for the library internal IDs
+const reservedDomain = "FAIL"
+
+const doc = `report misuse of github.com/MintzyG/fail/v3
+
+failvet checks rules the library only enforces at runtime, or can't enforce:
+
+  - fail.ID and fail.Form must be called at package level, not inside functions
+  - builders must not be called on package-level *fail.Error sentinels, they mutate shared state
+  - fail.Newf must not be used with static IDs, the message is ignored
+  - ID names must start with their domain, and the FAIL domain is reserved
+  - ID numbers must be sequential per domain and type across every file of the package
+
+Test files are ignored by the placement and numbering checks, and by the naming
+check inside functions.`
+
+// Analyzer is the failvet analyzer
+var Analyzer = &analysis.Analyzer{
+	Name:      "failvet",
+	Doc:       doc,
+	Run:       run,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(idFact)},
+}
+
+// sentinelBuilders can be disabled when the program runs with fail.SetCopyOnWrite(true)
+var sentinelBuilders bool
+
+func init() {
+	Analyzer.Flags.BoolVar(&sentinelBuilders, "sentinel-builders", true,
+		"report builder calls on package-level sentinels (disable when using fail.SetCopyOnWrite)")
+}
+
+// builders are the *fail.Error methods that mutate the receiver
+var builders = map[string]bool{
+	"System": true, "Domain": true, "Msg": true, "Msgf": true,
+	"Internal": true, "Internalf": true, "With": true, "WithLocale": true,
+	"WithArgs": true, "WithMeta": true, "AddMeta": true, "MergeMeta": true,
+	"Trace": true, "Traces": true, "Debug": true, "Debugs": true,
+	"RetryAfter": true, "Validation": true, "Validations": true,
+	"Render": true, "Localize": true,
+}
+
+// idFact records the constant arguments of a package-level fail.ID call
+type idFact struct {
+	Level  int
+	Domain string
+	Number int
+	Static bool
+	Name   string
+}
+
+func (*idFact) AFact() {}
+
+func (f *idFact) String() string {
+	kind := "D"
+	if f.Static {
+		kind = "S"
+	}
+	return fmt.Sprintf("%d_%s_%04d_%s", f.Level, f.Domain, f.Number, kind)
+}
+
+// declaredID is a fail.ID call found in the package
+type declaredID struct {
+	fact idFact
+	pos  token.Pos
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	var ids []declaredID
+
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		fn, _ := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != failPkg {
+			return true
+		}
+
+		// The library wraps its own registries, and tests register IDs at runtime on purpose
+		inFunc := insideFunc(stack) && pass.Pkg.Path() != failPkg
+		inTest := isTestFile(pass, call.Pos())
+		recv := receiverName(fn)
+
+		switch {
+		case fn.Name() == "ID" && (recv == "" || recv == "IDRegistry"):
+			if inFunc && !inTest {
+				pass.Reportf(call.Pos(), "fail.ID must be called at package level (var declaration), not inside a function")
+			}
+			fact, ok := constantID(pass, call)
+			if !ok {
+				return true
+			}
+			if !inFunc || !inTest {
+				checkIDName(pass, call, fact)
+			}
+			if recv == "" && !inFunc && !inTest {
+				ids = append(ids, declaredID{fact: fact, pos: call.Pos()})
+			}
+			exportIDFact(pass, call, stack, fact)
+
+		case fn.Name() == "Form" && (recv == "" || recv == "Registry"):
+			if inFunc && !inTest {
+				pass.Reportf(call.Pos(), "fail.Form must be called at package level (var declaration), not inside a function")
+			}
+
+		case fn.Name() == "Newf" && recv == "":
+			if len(call.Args) > 0 {
+				if fact := lookupIDFact(pass, call.Args[0]); fact != nil && fact.Static {
+					pass.Reportf(call.Pos(), "fail.Newf called with static ID %s (%s), the message is ignored", fact.Name, fact)
+				}
+			}
+
+		case fn.Name() == "SetMeta" && recv == "":
+			if sentinelBuilders && len(call.Args) > 0 {
+				if v := packageSentinel(pass, call.Args[0]); v != nil {
+					pass.Reportf(call.Pos(), "fail.SetMeta on package-level sentinel %s mutates shared state, use fail.New(id) instead", v.Name())
+				}
+			}
+
+		case recv == "Error" && builders[fn.Name()]:
+			sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+			if !sentinelBuilders || !ok {
+				return true
+			}
+			if v := packageSentinel(pass, sel.X); v != nil {
+				pass.Reportf(call.Pos(), "%s called on package-level sentinel %s mutates shared state, use fail.New(id) instead", fn.Name(), v.Name())
+			}
+		}
+		return true
+	})
+
+	checkNumbering(pass, ids)
+	return nil, nil
+}
+
+// checkIDName reports names that don't start with their domain and the reserved domain
+func checkIDName(pass *analysis.Pass, call *ast.CallExpr, fact idFact) {
+	if fact.Domain == reservedDomain {
+		pass.Reportf(call.Pos(), "domain %q is reserved for internal errors", reservedDomain)
+		return
+	}
+	if !strings.HasPrefix(strings.ToLower(fact.Name), strings.ToLower(fact.Domain)) {
+		pass.Reportf(call.Pos(), "error name %q must start with domain %q", fact.Name, fact.Domain)
+	}
+}
+
+// checkNumbering reports duplicate numbers and gaps per domain and type across the package
+func checkNumbering(pass *analysis.Pass, ids []declaredID) {
+	type group struct {
+		domain string
+		static bool
+	}
+	groups := make(map[group][]declaredID)
+	for _, id := range ids {
+		g := group{id.fact.Domain, id.fact.Static}
+		groups[g] = append(groups[g], id)
+	}
+
+	keys := make([]group, 0, len(groups))
+	for g := range groups {
+		keys = append(keys, g)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].domain != keys[j].domain {
+			return keys[i].domain < keys[j].domain
+		}
+		return keys[i].static
+	})
+
+	for _, g := range keys {
+		list := groups[g]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].fact.Number < list[j].fact.Number })
+
+		expected := 0
+		for i, id := range list {
+			if i > 0 && id.fact.Number == list[i-1].fact.Number {
+				pass.Reportf(id.pos, "number %d already used in %s (static=%v) by %q",
+					id.fact.Number, g.domain, g.static, list[i-1].fact.Name)
+				continue
+			}
+			if id.fact.Number != expected {
+				missing := fmt.Sprint(expected)
+				if id.fact.Number-expected > 1 {
+					missing = fmt.Sprintf("%d-%d", expected, id.fact.Number-1)
+				}
+				pass.Reportf(id.pos, "ID numbering gap in %s (static=%v): missing %s before %q",
+					g.domain, g.static, missing, id.fact.Name)
+			}
+			expected = id.fact.Number + 1
+		}
+	}
+}
+
+// constantID extracts the arguments of ID(level, domain, number, static, name) if they are constants
+func constantID(pass *analysis.Pass, call *ast.CallExpr) (idFact, bool) {
+	if len(call.Args) != 5 {
+		return idFact{}, false
+	}
+	values := make([]constant.Value, 5)
+	for i, arg := range call.Args {
+		tv, ok := pass.TypesInfo.Types[arg]
+		if !ok || tv.Value == nil {
+			return idFact{}, false
+		}
+		values[i] = tv.Value
+	}
+
+	level, ok1 := constant.Int64Val(values[0])
+	number, ok2 := constant.Int64Val(values[2])
+	if !ok1 || !ok2 || values[1].Kind() != constant.String ||
+		values[3].Kind() != constant.Bool || values[4].Kind() != constant.String {
+		return idFact{}, false
+	}
+
+	return idFact{
+		Level:  int(level),
+		Domain: constant.StringVal(values[1]),
+		Number: int(number),
+		Static: constant.BoolVal(values[3]),
+		Name:   constant.StringVal(values[4]),
+	}, true
+}
+
+// exportIDFact attaches fact to the package-level variable initialized by call, if any
+func exportIDFact(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node, fact idFact) {
+	if len(stack) < 2 {
+		return
+	}
+	spec, ok := stack[len(stack)-2].(*ast.ValueSpec)
+	if !ok {
+		return
+	}
+	for i, value := range spec.Values {
+		if value != call || i >= len(spec.Names) {
+			continue
+		}
+		if obj := pass.TypesInfo.Defs[spec.Names[i]]; obj != nil && isPackageLevel(obj) {
+			f := fact
+			pass.ExportObjectFact(obj, &f)
+		}
+	}
+}
+
+// lookupIDFact returns the ID fact of the variable referenced by expr, from any package
+func lookupIDFact(pass *analysis.Pass, expr ast.Expr) *idFact {
+	obj := referencedObject(pass, expr)
+	if obj == nil {
+		return nil
+	}
+	var fact idFact
+	if !pass.ImportObjectFact(obj, &fact) {
+		return nil
+	}
+	return &fact
+}
+
+// packageSentinel returns the package-level *fail.Error variable referenced by expr, if any
+func packageSentinel(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	v, ok := referencedObject(pass, expr).(*types.Var)
+	if !ok || !isPackageLevel(v) {
+		return nil
+	}
+	ptr, ok := v.Type().(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != failPkg || named.Obj().Name() != "Error" {
+		return nil
+	}
+	return v
+}
+
+// referencedObject resolves an identifier or a qualified identifier (pkg.Name)
+func referencedObject(pass *analysis.Pass, expr ast.Expr) types.Object {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return pass.TypesInfo.Uses[e]
+	case *ast.SelectorExpr:
+		return pass.TypesInfo.Uses[e.Sel]
+	}
+	return nil
+}
+
+func isPackageLevel(obj types.Object) bool {
+	return obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope()
+}
+
+func receiverName(fn *types.Func) string {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return ""
+	}
+	t := sig.Recv().Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+func insideFunc(stack []ast.Node) bool {
+	for _, n := range stack {
+		switch n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return true
+		}
+	}
+	return false
+}
+
+func isTestFile(pass *analysis.Pass, pos token.Pos) bool {
+	return strings.HasSuffix(pass.Fset.File(pos).Name(), "_test.go")
+}
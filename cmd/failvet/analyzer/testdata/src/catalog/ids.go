@@ -0,0 +1,16 @@
+package catalog
+
+import "github.com/MintzyG/fail/v3"
+
+var (
+	AuthInvalid  = fail.ID(0, "AUTH", 0, true, "AuthInvalid")   // want AuthInvalid:"0_AUTH_0000_S"
+	AuthExpired  = fail.ID(0, "AUTH", 1, true, "AuthExpired")   // want AuthExpired:"0_AUTH_0001_S"
+	AuthCustom   = fail.ID(0, "AUTH", 0, false, "AuthCustom")   // want AuthCustom:"0_AUTH_0000_D"
+	UserMissing  = fail.ID(0, "USER", 0, false, "UserMissing")  // want UserMissing:"0_USER_0000_D"
+	UserDisabled = fail.ID(0, "USER", 2, false, "UserDisabled") // want `ID numbering gap in USER \(static=false\): missing 1 before "UserDisabled"` UserDisabled:"0_USER_0002_D"
+
+	BadName  = fail.ID(0, "BILLING", 0, true, "InvoiceLate") // want `error name "InvoiceLate" must start with domain "BILLING"` BadName:"0_BILLING_0000_S"
+	Reserved = fail.ID(0, "FAIL", 0, true, "FailSomething")  // want `domain "FAIL" is reserved for internal errors` Reserved:"0_FAIL_0000_S"
+
+	ErrAuthInvalid = fail.Form(AuthInvalid, "invalid credentials", false, nil)
+)
@@ -0,0 +1,10 @@
+package catalog
+
+import "github.com/MintzyG/fail/v3"
+
+// Numbering is checked across files of the package
+var (
+	AuthLocked    = fail.ID(0, "AUTH", 2, true, "AuthLocked")    // want AuthLocked:"0_AUTH_0002_S"
+	AuthDuplicate = fail.ID(0, "AUTH", 2, true, "AuthDuplicate") // want `number 2 already used in AUTH \(static=true\) by "AuthLocked"` AuthDuplicate:"0_AUTH_0002_S"
+	AuthFarAway   = fail.ID(0, "AUTH", 6, true, "AuthFarAway")   // want `ID numbering gap in AUTH \(static=true\): missing 3-5 before "AuthFarAway"` AuthFarAway:"0_AUTH_0006_S"
+)
@@ -0,0 +1,42 @@
+// Package fail is a minimal stub of github.com/MintzyG/fail/v3 for analyzer tests
+package fail
+
+type ErrorID struct{}
+
+type Error struct{}
+
+type IDRegistry struct{}
+
+type Registry struct{}
+
+type MetaKey[T any] struct{}
+
+func ID(level int, domain string, number int, static bool, name string) ErrorID { return ErrorID{} }
+
+func (r *IDRegistry) ID(level int, domain string, number int, static bool, name string) ErrorID {
+	return ErrorID{}
+}
+
+func Form(id ErrorID, defaultMsg string, isSystem bool, meta map[string]any, defaultArgs ...any) *Error {
+	return &Error{}
+}
+
+func (r *Registry) Form(id ErrorID, defaultMsg string, isSystem bool, meta map[string]any, defaultArgs ...any) *Error {
+	return &Error{}
+}
+
+func New(id ErrorID) *Error { return &Error{} }
+
+func Newf(id ErrorID, format string, args ...any) *Error { return &Error{} }
+
+func SetMeta[T any](err *Error, key MetaKey[T], value T) *Error { return err }
+
+func NewMetaKey[T any](name string) MetaKey[T] { return MetaKey[T]{} }
+
+func (e *Error) AddMeta(key string, value any) *Error { return e }
+
+func (e *Error) Msg(msg string) *Error { return e }
+
+func (e *Error) GetRendered() string { return "" }
+
+func (e *Error) Error() string { return "" }
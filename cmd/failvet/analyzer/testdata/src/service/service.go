@@ -0,0 +1,32 @@
+package service
+
+import (
+	"catalog"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	ServiceLocal = fail.ID(0, "SERVICE", 0, false, "ServiceLocal") // want ServiceLocal:"0_SERVICE_0000_D"
+
+	errLocal = fail.Form(ServiceLocal, "local", false, nil)
+
+	requestID = fail.NewMetaKey[string]("request_id")
+)
+
+func handler(user string) error {
+	runtimeID := fail.ID(0, "SERVICE", 1, false, "ServiceRuntime") // want `fail.ID must be called at package level`
+	_ = fail.Form(runtimeID, "runtime", false, nil)                // want `fail.Form must be called at package level`
+
+	_ = fail.Newf(catalog.AuthInvalid, "user %s", user) // want `fail.Newf called with static ID AuthInvalid \(0_AUTH_0000_S\), the message is ignored`
+	_ = fail.Newf(catalog.AuthCustom, "user %s", user)
+	_ = fail.Newf(ServiceLocal, "user %s", user)
+
+	_ = catalog.ErrAuthInvalid.AddMeta("user", user) // want `AddMeta called on package-level sentinel ErrAuthInvalid mutates shared state`
+	_ = errLocal.Msg("changed")                      // want `Msg called on package-level sentinel errLocal mutates shared state`
+	_ = fail.SetMeta(errLocal, requestID, "abc")     // want `fail.SetMeta on package-level sentinel errLocal mutates shared state`
+	_ = errLocal.GetRendered()
+
+	local := fail.New(ServiceLocal)
+	return local.AddMeta("user", user)
+}
@@ -0,0 +1,21 @@
+// Command failvet reports misuse of github.com/MintzyG/fail/v3 that the library
+// can only detect at runtime, or not at all
+//
+// Usage:
+//
+//	go install github.com/MintzyG/fail/v3/cmd/failvet@latest
+//	failvet ./...
+//
+// Or as a vet tool:
+//
+//	go vet -vettool=$(which failvet) ./...
+package main
+
+import (
+	"github.com/MintzyG/fail/v3/cmd/failvet/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
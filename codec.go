@@ -0,0 +1,426 @@
+package fail
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// CodecVersion is the version of the binary layout produced by Codec.Encode
+const CodecVersion = 1
+
+// CodecHeader is the conventional header/metadata key used to carry an encoded error
+const CodecHeader = "X-Fail-Error"
+
+// codecDefaultMetaKeys always cross the boundary, they drive retry decisions on the caller
+var codecDefaultMetaKeys = []string{MetaRetryable.Name(), MetaRetryAfter.Name()}
+
+// Record flags
+const (
+	codecFlagID     = 1 << iota // record carries an ErrorID, otherwise it's a generic cause
+	codecFlagSystem             // IsSystem
+	codecFlagStatic             // ID is static
+	codecFlagCause              // a cause record follows
+)
+
+// Value tags
+const (
+	codecValueNil byte = iota
+	codecValueString
+	codecValueBool
+	codecValueInt
+	codecValueUint
+	codecValueFloat
+	codecValueDuration
+)
+
+// CodecConfig configures a Codec
+type CodecConfig struct {
+	// Registry re-hydrates decoded errors (default: global registry)
+	Registry *Registry
+
+	// MetaKeys lists the Meta keys allowed to cross the boundary, in both directions
+	// "retryable" and "retry_after" are always allowed. Only string, bool, integer,
+	// float and time.Duration values are encoded, other values are dropped
+	MetaKeys []string
+}
+
+// Codec encodes *Error values into a compact binary form for propagation across
+// services and decodes them back into errors of the local Registry
+//
+// Both sides are expected to import the same ID catalog, so the ID is enough to
+// restore a registered error and fail.Is keeps working after the hop. Unknown IDs
+// decode into untrusted errors (IsRegistered() == false).
+//
+// What crosses the boundary:
+//   - ID, IsSystem, Locale and Args
+//   - Message, only for dynamic IDs, static messages are owned by the local definition
+//   - Meta keys allowed by CodecConfig.MetaKeys
+//   - The cause chain, generic causes keep only their message
+//
+// InternalMessage never crosses the boundary
+type Codec struct {
+	registry *Registry
+	metaKeys map[string]struct{}
+}
+
+// NewCodec creates a codec
+//
+// Example:
+//
+//	codec := fail.NewCodec(fail.CodecConfig{MetaKeys: []string{"request_id"}})
+//
+//	// server
+//	w.Header().Set(fail.CodecHeader, codec.EncodeString(err))
+//
+//	// client
+//	err, _ := codec.DecodeString(resp.Header.Get(fail.CodecHeader))
+//	if fail.Is(err, UserNotFound) { ... }
+func NewCodec(config CodecConfig) *Codec {
+	if config.Registry == nil {
+		config.Registry = global
+	}
+
+	keys := make(map[string]struct{}, len(codecDefaultMetaKeys)+len(config.MetaKeys))
+	for _, k := range codecDefaultMetaKeys {
+		keys[k] = struct{}{}
+	}
+	for _, k := range config.MetaKeys {
+		keys[k] = struct{}{}
+	}
+
+	return &Codec{registry: config.Registry, metaKeys: keys}
+}
+
+// Encode returns the binary form of err, nil if err is nil
+func (c *Codec) Encode(err *Error) []byte {
+	if err == nil {
+		return nil
+	}
+	buf := []byte{CodecVersion}
+	return c.appendRecord(buf, err, 0)
+}
+
+// EncodeString returns the header-safe form of err (unpadded base64url), "" if err is nil
+func (c *Codec) EncodeString(err *Error) string {
+	if err == nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(c.Encode(err))
+}
+
+// Decode re-hydrates the output of Encode into an *Error of the codec registry
+// Returns an ErrorPayloadInvalid error if data can't be decoded
+func (c *Codec) Decode(data []byte) (*Error, error) {
+	if len(data) == 0 {
+		return nil, New(ErrorPayloadInvalid).WithArgs("empty payload").Render()
+	}
+	if data[0] != CodecVersion {
+		return nil, New(ErrorPayloadInvalid).
+			WithArgs(fmt.Sprintf("unsupported version %d", data[0])).
+			AddMeta("version", int(data[0])).
+			Render()
+	}
+
+	d := &codecDecoder{data: data[1:]}
+	payload := c.readRecord(d, 0)
+	if d.err == nil && len(d.data) > 0 {
+		d.fail("trailing bytes")
+	}
+	if d.err != nil {
+		return nil, New(ErrorPayloadInvalid).WithArgs(d.err.Error()).Render()
+	}
+	if payload.ID == "" {
+		return nil, New(ErrorPayloadInvalid).WithArgs("missing id").Render()
+	}
+
+	return c.registry.hydrate(payload, 0), nil
+}
+
+// DecodeString decodes the output of EncodeString
+func (c *Codec) DecodeString(s string) (*Error, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, New(ErrorPayloadInvalid).WithArgs("malformed base64").With(err).Render()
+	}
+	return c.Decode(data)
+}
+
+func (c *Codec) appendRecord(buf []byte, err error, depth int) []byte {
+	var cause error
+	if depth < maxCauseDepth {
+		if e, ok := err.(*Error); ok {
+			cause = e.Cause
+		} else {
+			cause = errors.Unwrap(err)
+		}
+	}
+
+	var flags uint64
+	if cause != nil {
+		flags |= codecFlagCause
+	}
+
+	e, ok := err.(*Error)
+	if !ok {
+		buf = binary.AppendUvarint(buf, flags)
+		buf = appendCodecString(buf, err.Error())
+	} else {
+		flags |= codecFlagID
+		if e.IsSystem {
+			flags |= codecFlagSystem
+		}
+		if e.ID.IsStatic() {
+			flags |= codecFlagStatic
+		}
+		buf = binary.AppendUvarint(buf, flags)
+		buf = appendCodecString(buf, e.ID.String())
+		buf = appendCodecString(buf, e.ID.Name())
+
+		message := ""
+		if !e.ID.IsStatic() {
+			message = e.Message
+		}
+		buf = appendCodecString(buf, message)
+		buf = appendCodecString(buf, e.Locale)
+
+		buf = binary.AppendUvarint(buf, uint64(len(e.Args)))
+		for _, arg := range e.Args {
+			// Args are needed to render the message, keep a readable form of unsupported values
+			if !codecSupported(arg) {
+				arg = fmt.Sprintf("%v", arg)
+			}
+			buf = appendCodecValue(buf, arg)
+		}
+
+		keys := make([]string, 0, len(e.Meta))
+		for k, v := range e.Meta {
+			if _, allowed := c.metaKeys[k]; allowed && codecSupported(v) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys) // deterministic output
+		buf = binary.AppendUvarint(buf, uint64(len(keys)))
+		for _, k := range keys {
+			buf = appendCodecString(buf, k)
+			buf = appendCodecValue(buf, e.Meta[k])
+		}
+	}
+
+	if cause != nil {
+		buf = c.appendRecord(buf, cause, depth+1)
+	}
+	return buf
+}
+
+func (c *Codec) readRecord(d *codecDecoder, depth int) *errorJSON {
+	if depth > maxCauseDepth {
+		d.fail("cause chain too deep")
+		return nil
+	}
+
+	flags := d.uvarint()
+	p := &errorJSON{}
+
+	if flags&codecFlagID == 0 {
+		p.Message = d.string()
+	} else {
+		idString := d.string()
+		p.Name = d.string()
+		p.Message = d.string()
+		p.Locale = d.string()
+		p.System = flags&codecFlagSystem != 0
+
+		if id, ok := parseErrorID(idString); ok {
+			p.ID = idString
+			p.Domain = id.domain
+			p.Level = id.level
+			p.Number = id.number
+			p.Static = id.isStatic
+		} else if d.err == nil {
+			d.fail("malformed id")
+		}
+
+		if n := d.count(); n > 0 {
+			p.Args = make([]any, n)
+			for i := range p.Args {
+				p.Args[i] = d.value()
+			}
+		}
+
+		if n := d.count(); n > 0 {
+			p.Meta = make(map[string]any, n)
+			for i := 0; i < n; i++ {
+				k := d.string()
+				v := d.value()
+				// Filter on the way in too, never trust the sender's allowlist
+				if _, allowed := c.metaKeys[k]; allowed {
+					p.Meta[k] = v
+				}
+			}
+		}
+	}
+
+	if flags&codecFlagCause != 0 && d.err == nil {
+		p.Cause = c.readRecord(d, depth+1)
+	}
+	return p
+}
+
+func codecSupported(v any) bool {
+	switch v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64, time.Duration:
+		return true
+	}
+	return false
+}
+
+func appendCodecString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// appendCodecValue appends a tagged value, v must be codecSupported
+func appendCodecValue(buf []byte, v any) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendCodecString(append(buf, codecValueString), v)
+	case bool:
+		b := byte(0)
+		if v {
+			b = 1
+		}
+		return append(buf, codecValueBool, b)
+	case time.Duration:
+		return binary.AppendVarint(append(buf, codecValueDuration), int64(v))
+	case int:
+		return binary.AppendVarint(append(buf, codecValueInt), int64(v))
+	case int8:
+		return binary.AppendVarint(append(buf, codecValueInt), int64(v))
+	case int16:
+		return binary.AppendVarint(append(buf, codecValueInt), int64(v))
+	case int32:
+		return binary.AppendVarint(append(buf, codecValueInt), int64(v))
+	case int64:
+		return binary.AppendVarint(append(buf, codecValueInt), v)
+	case uint:
+		return binary.AppendUvarint(append(buf, codecValueUint), uint64(v))
+	case uint8:
+		return binary.AppendUvarint(append(buf, codecValueUint), uint64(v))
+	case uint16:
+		return binary.AppendUvarint(append(buf, codecValueUint), uint64(v))
+	case uint32:
+		return binary.AppendUvarint(append(buf, codecValueUint), uint64(v))
+	case uint64:
+		return binary.AppendUvarint(append(buf, codecValueUint), v)
+	case float32:
+		return binary.BigEndian.AppendUint64(append(buf, codecValueFloat), math.Float64bits(float64(v)))
+	case float64:
+		return binary.BigEndian.AppendUint64(append(buf, codecValueFloat), math.Float64bits(v))
+	default:
+		return append(buf, codecValueNil)
+	}
+}
+
+// codecDecoder reads a payload, the first failure sticks and every later read returns zero values
+type codecDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *codecDecoder) fail(reason string) {
+	if d.err == nil {
+		d.err = errors.New(reason)
+	}
+	d.data = nil
+}
+
+func (d *codecDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("truncated payload")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *codecDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail("truncated payload")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// count reads a length and checks it against the remaining bytes to bound allocations
+func (d *codecDecoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail("truncated payload")
+		return 0
+	}
+	return int(n)
+}
+
+func (d *codecDecoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > len(d.data) {
+		d.fail("truncated payload")
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *codecDecoder) string() string {
+	return string(d.bytes(d.count()))
+}
+
+func (d *codecDecoder) value() any {
+	tag := d.bytes(1)
+	if tag == nil {
+		return nil
+	}
+
+	switch tag[0] {
+	case codecValueNil:
+		return nil
+	case codecValueString:
+		return d.string()
+	case codecValueBool:
+		b := d.bytes(1)
+		return b != nil && b[0] == 1
+	case codecValueInt:
+		return int(d.varint())
+	case codecValueUint:
+		return d.uvarint()
+	case codecValueFloat:
+		b := d.bytes(8)
+		if b == nil {
+			return nil
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b))
+	case codecValueDuration:
+		return time.Duration(d.varint())
+	default:
+		d.fail(fmt.Sprintf("unknown value tag %d", tag[0]))
+		return nil
+	}
+}
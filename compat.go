@@ -0,0 +1,199 @@
+package fail
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CompatChangeKind classifies a difference between two ID catalogs
+type CompatChangeKind string
+
+const (
+	CompatRemoved       CompatChangeKind = "removed"        // ID no longer exists
+	CompatRenamed       CompatChangeKind = "renamed"        // Same ID, new name (breaking, see CompareIDLists)
+	CompatRenumbered    CompatChangeKind = "renumbered"     // Same name, new number
+	CompatLevelChanged  CompatChangeKind = "level_changed"  // Same name, new level
+	CompatStaticFlipped CompatChangeKind = "static_flipped" // Same name, static <-> dynamic
+	CompatDomainMoved   CompatChangeKind = "domain_moved"   // Same name, new domain
+	CompatAdded         CompatChangeKind = "added"          // New ID
+	CompatDeprecated    CompatChangeKind = "deprecated"     // ID deprecated in favor of another, see Deprecate
+)
+
+// CompatChange is a single difference between two ID catalogs
+type CompatChange struct {
+	Kind     CompatChangeKind `json:"kind"`
+	Breaking bool             `json:"breaking"`
+	Old      *IDListEntry     `json:"old,omitempty"` // nil for CompatAdded
+	New      *IDListEntry     `json:"new,omitempty"` // nil for CompatRemoved
+}
+
+// String returns a one line description (e.g., "breaking: AuthLocked renumbered 0_AUTH_0001_S -> 0_AUTH_0002_S")
+func (c CompatChange) String() string {
+	class := "non-breaking"
+	if c.Breaking {
+		class = "breaking"
+	}
+
+	switch c.Kind {
+	case CompatAdded:
+		return fmt.Sprintf("%s: %s added as %s", class, c.New.Name, c.New.ID)
+	case CompatRemoved:
+		return fmt.Sprintf("%s: %s (%s) removed", class, c.Old.Name, c.Old.ID)
+	case CompatDeprecated:
+		return fmt.Sprintf("%s: %s (%s) deprecated, use %s", class, c.New.Name, c.New.ID, c.New.ReplacedBy)
+	case CompatRenamed:
+		return fmt.Sprintf("%s: %s renamed to %s (%s)", class, c.Old.Name, c.New.Name, c.New.ID)
+	default:
+		return fmt.Sprintf("%s: %s %s %s -> %s", class, c.Old.Name,
+			strings.ReplaceAll(string(c.Kind), "_", " "), c.Old.ID, c.New.ID)
+	}
+}
+
+// CompatReport lists the differences between two ID catalogs, old catalog order first
+type CompatReport struct {
+	Changes []CompatChange `json:"changes"`
+}
+
+// Breaking reports whether any change breaks the stability promise
+func (r *CompatReport) Breaking() bool {
+	for _, c := range r.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// BreakingChanges returns the breaking changes only
+func (r *CompatReport) BreakingChanges() []CompatChange {
+	var out []CompatChange
+	for _, c := range r.Changes {
+		if c.Breaking {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// CompareIDLists compares two outputs of ExportIDList, typically the catalog of the last
+// release against the current one, and reports how IDs moved
+//
+// The ID string (e.g., "0_AUTH_0001_S") is the identity persisted in logs, databases and
+// payloads, so every change that makes an existing ID string disappear is breaking:
+// removals, renumbering, level changes, static/dynamic flips and domain moves. Renames are
+// breaking too: the name is the Go identifier of the ID, it is exported as "name" and
+// LookupByName and LookupIDByName resolve it. Additions and deprecations are non-breaking.
+//
+// Errors are matched by name first, a name missing from the new catalog whose
+// domain, type and number are taken by a new name is reported as renamed.
+//
+// Returns a CatalogInvalid error if either catalog can't be decoded
+//
+// Example:
+//
+//	report, err := fail.CompareIDLists(released, current)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, c := range report.BreakingChanges() {
+//	    fmt.Println(c)
+//	}
+func CompareIDLists(oldList, newList []byte) (*CompatReport, error) {
+	oldEntries, err := decodeIDList(oldList, "old")
+	if err != nil {
+		return nil, err
+	}
+	newEntries, err := decodeIDList(newList, "new")
+	if err != nil {
+		return nil, err
+	}
+	return CompareIDEntries(oldEntries, newEntries), nil
+}
+
+// CompareIDEntries compares two decoded catalogs, see CompareIDLists
+func CompareIDEntries(oldEntries, newEntries []IDListEntry) *CompatReport {
+	newByName := make(map[string]*IDListEntry, len(newEntries))
+	newBySlot := make(map[string]*IDListEntry, len(newEntries))
+	for i := range newEntries {
+		e := &newEntries[i]
+		newByName[e.Name] = e
+		newBySlot[idSlot(e)] = e
+	}
+	oldNames := make(map[string]bool, len(oldEntries))
+	for _, e := range oldEntries {
+		oldNames[e.Name] = true
+	}
+
+	report := &CompatReport{}
+	matched := make(map[string]bool) // new names accounted for
+	add := func(kind CompatChangeKind, breaking bool, o, n *IDListEntry) {
+		report.Changes = append(report.Changes, CompatChange{Kind: kind, Breaking: breaking, Old: o, New: n})
+	}
+
+	for i := range oldEntries {
+		o := &oldEntries[i]
+
+		if n, ok := newByName[o.Name]; ok {
+			matched[n.Name] = true
+			if o.Domain != n.Domain {
+				add(CompatDomainMoved, true, o, n)
+			}
+			if o.Static != n.Static {
+				add(CompatStaticFlipped, true, o, n)
+			}
+			if o.Domain == n.Domain && o.Static == n.Static && o.Number != n.Number {
+				add(CompatRenumbered, true, o, n)
+			}
+			if o.Level != n.Level {
+				add(CompatLevelChanged, true, o, n)
+			}
+			if o.Status != IDStatusDeprecated && n.Status == IDStatusDeprecated {
+				add(CompatDeprecated, false, o, n)
+			}
+			continue
+		}
+
+		if n, ok := newBySlot[idSlot(o)]; ok && !oldNames[n.Name] && !matched[n.Name] {
+			matched[n.Name] = true
+			add(CompatRenamed, true, o, n)
+			if o.Level != n.Level {
+				add(CompatLevelChanged, true, o, n)
+			}
+			continue
+		}
+
+		add(CompatRemoved, true, o, nil)
+	}
+
+	for i := range newEntries {
+		if n := &newEntries[i]; !matched[n.Name] {
+			add(CompatAdded, false, nil, n)
+		}
+	}
+	return report
+}
+
+// idSlot is the position of an ID in the numbering, the level doesn't take part in it
+func idSlot(e *IDListEntry) string {
+	return fmt.Sprintf("%s:%v:%d", e.Domain, e.Static, e.Number)
+}
+
+func decodeIDList(data []byte, which string) ([]IDListEntry, error) {
+	var entries []IDListEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, New(CatalogInvalid).WithArgs(which + " catalog is not an ExportIDList output").With(err).Render()
+	}
+
+	names := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if e.Name == "" || e.Domain == "" {
+			return nil, New(CatalogInvalid).WithArgs(which + " catalog has an entry without name or domain").Render()
+		}
+		if names[e.Name] {
+			return nil, New(CatalogInvalid).WithArgs(fmt.Sprintf("%s catalog lists %s twice", which, e.Name)).Render()
+		}
+		names[e.Name] = true
+	}
+	return entries, nil
+}
@@ -20,8 +20,8 @@ func New(id ErrorID) *Error {
 
 // Newf returns a new Error from a registered definition with a new formatted message
 func Newf(id ErrorID, format string, args ...interface{}) *Error {
-	err := New(id)
-	if err.checkStatic("Newf") {
+	err, abort := New(id).prepare("Newf")
+	if abort {
 		return err
 	}
 	err.Message = fmt.Sprintf(format, args...)
@@ -32,7 +32,7 @@ func Newf(id ErrorID, format string, args ...interface{}) *Error {
 // This is a convenience function for defining error sentinels
 //
 // WARNING Only use package level sentinel errors that are created by Form in non-concurrent environments
-// For concurrent environment prefer calling New with the error ID
+// For concurrent environment prefer calling New with the error ID, or enable SetCopyOnWrite
 //
 // Example:
 //
@@ -82,7 +82,9 @@ func (r *Registry) Form(id ErrorID, defaultMsg string, isSystem bool, meta map[s
 	r.Register(tmpl)
 	global.hooks.runForm(id, tmpl)
 
-	return r.New(id)
+	sentinel := r.newError(id, false)
+	sentinel.shared = true
+	return sentinel
 }
 
 // Error is the core error type that all domain errors implement
@@ -106,6 +108,9 @@ type Error struct {
 	registry      *Registry
 	createdByFrom bool
 	isStatic      bool
+	shared        bool      // Registry template or Form sentinel, cloned by builders in copy-on-write mode
+	owned         bool      // Clone made by copy-on-write, builders modify it in place
+	stack         []uintptr // Program counters captured at creation, see StackTrace
 }
 
 // Error() uses GetRendered() for the final message
@@ -193,6 +198,110 @@ func (e *Error) checkStatic(builderName string) bool {
 	return true
 }
 
+// staticLockedBuilders change the message or classification, they stay blocked on
+// static errors even in copy-on-write mode
+var staticLockedBuilders = map[string]bool{
+	"System":   true,
+	"Domain":   true,
+	"Msg":      true,
+	"Msgf":     true,
+	"WithArgs": true,
+	"Newf":     true,
+}
+
+// staticExemptBuilders only set hints that don't touch the message or classification,
+// they are allowed on static errors even without copy-on-write
+var staticExemptBuilders = map[string]bool{
+	"RetryAfter": true,
+}
+
+// prepare returns the error a builder should modify and whether the builder must abort.
+// It should only ever be called by builder methods.
+//
+// Without copy-on-write it returns e and the result of checkStatic.
+// With copy-on-write shared errors (templates and Form sentinels) and static errors
+// are cloned first, static errors still block builders in staticLockedBuilders.
+// Builders in staticExemptBuilders never abort on static errors.
+// Clones are owned by the caller, so later builders in the same chain modify them in place.
+func (e *Error) prepare(builderName string) (*Error, bool) {
+	reg := e.registry
+	if reg == nil {
+		reg = global
+	}
+
+	if !reg.copyOnWrite {
+		if staticExemptBuilders[builderName] {
+			return e, false
+		}
+		return e, e.checkStatic(builderName)
+	}
+
+	if e.isStatic && staticLockedBuilders[builderName] {
+		return e, e.checkStatic(builderName)
+	}
+
+	if e.shared || (e.isStatic && !e.owned) {
+		return e.cowClone(), false
+	}
+	return e, false
+}
+
+// unshare returns a clone of e if it's shared and copy-on-write is enabled, e otherwise
+func (e *Error) unshare() *Error {
+	reg := e.registry
+	if reg == nil {
+		reg = global
+	}
+
+	if reg.copyOnWrite && e.shared {
+		return e.cowClone()
+	}
+	return e
+}
+
+// cowClone returns an owned clone of e whose slice meta values (traces, debug,
+// validations, ...) are copied too, so appending to them never writes into the
+// backing arrays of e while other goroutines clone it
+func (e *Error) cowClone() *Error {
+	clone := e.Clone()
+	clone.owned = true
+	for k, v := range clone.Meta {
+		switch v.(type) {
+		case []string, []ValidationError, []any, RetryHistory:
+			clone.Meta[k] = cloneValue(v, 0)
+		}
+	}
+	return clone
+}
+
+// SetCopyOnWrite enables or disables copy-on-write mode on the global registry
+// See Registry.SetCopyOnWrite
+func SetCopyOnWrite(enabled bool) {
+	global.SetCopyOnWrite(enabled)
+}
+
+// SetCopyOnWrite enables or disables copy-on-write mode for this registry. Default is false.
+//
+// When enabled, builders called on a shared error (a sentinel returned by Form or a
+// registry template) return a fresh clone instead of mutating it, so package-level
+// sentinels are safe to use concurrently. Builders on static errors also return a
+// clone, with only the fields that don't change the message or classification
+// modified (System, Domain, Msg, Msgf, WithArgs and Newf are still blocked).
+//
+// Always use the returned error, the receiver may be left untouched:
+//
+//	var ErrNotFound = fail.Form(UserNotFound, "user not found", false, nil)
+//
+//	fail.SetCopyOnWrite(true)
+//	err := ErrNotFound.AddMeta("user_id", id) // ErrNotFound is unchanged
+//
+// Configure it at startup, before errors are built concurrently.
+func (r *Registry) SetCopyOnWrite(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.copyOnWrite = enabled
+}
+
 // Unwrap implements error unwrapping for errors.Is/As
 func (e *Error) Unwrap() error {
 	if e.Cause != nil {
@@ -208,17 +317,23 @@ type ErrorDefinition struct {
 	IsSystem       bool
 	Meta           map[string]any // Default metadata to include
 	DefaultArgs    []any
+	RetryPolicy    *RetryPolicy // Optional retry policy for this ID, see SetRetryPolicy
 }
 
 // Register adds an error definition to the global registry
 func Register(def ErrorDefinition) {
-	global.Register(&Error{
+	if err := global.Register(&Error{
 		ID:       def.ID,
 		Message:  def.DefaultMessage,
 		IsSystem: def.IsSystem,
 		Meta:     def.Meta,
 		isStatic: def.ID.IsStatic(),
-	})
+	}); err != nil {
+		return
+	}
+	if def.RetryPolicy != nil {
+		_ = global.SetRetryPolicy(def.ID, *def.RetryPolicy)
+	}
 }
 
 // AllowInternalLogs enables or disables internal library logging.
@@ -0,0 +1,234 @@
+package fail
+
+import (
+	"fmt"
+	"log"
+)
+
+// maxAliasDepth bounds the replacement chain followed when resolving a deprecated ID
+const maxAliasDepth = 16
+
+// Deprecation describes a retired ID and the ID that replaces it
+type Deprecation struct {
+	ID          ErrorID
+	Replacement ErrorID
+	Reason      string
+}
+
+// Deprecate marks id as deprecated in favor of replacement on the global ID registry
+// See IDRegistry.Deprecate
+func Deprecate(id, replacement ErrorID, reason string) *Error {
+	return globalIDRegistry.Deprecate(id, replacement, reason)
+}
+
+// Deprecate marks id as deprecated in favor of replacement
+//
+// Numbers are never reused and gaps are forbidden, so a retired ID keeps its fail.ID
+// declaration and its number. Deprecating it makes it an alias of replacement:
+//   - Is, errors.Is and Match treat the alias and its replacement as the same error
+//   - Registry.New on the alias fires the HookDeprecated hooks and, with internal
+//     logs enabled, logs the replacement
+//   - ExportIDList reports the alias as deprecated
+//
+// Replacements can be deprecated in turn, an alias matches every ID further down its
+// chain, chains are limited to maxAliasDepth (16) hops. Two IDs deprecated into the
+// same replacement don't match each other.
+// New, Is and Match consult the global ID registry, deprecations on custom ID
+// registries only show up in their ExportIDList and GetDeprecation.
+//
+// Returns an IDDeprecationInvalid error if either ID is unknown to this registry,
+// if id is already deprecated, if the replacement chain would loop back to id or
+// would be too long
+//
+// Example:
+//
+//	var (
+//	    AuthTokenInvalid  = fail.ID(0, "AUTH", 3, true, "AuthTokenInvalid") // retired
+//	    AuthTokenRejected = fail.ID(0, "AUTH", 4, true, "AuthTokenRejected")
+//	)
+//
+//	var _ = fail.Deprecate(AuthTokenInvalid, AuthTokenRejected, "split into expired/revoked")
+func (r *IDRegistry) Deprecate(id, replacement ErrorID, reason string) *Error {
+	// The error is built after unlocking, New looks up deprecations itself
+	if why := r.deprecate(id, replacement, reason); why != "" {
+		return New(IDDeprecationInvalid).WithArgs(id.String(), why).Render()
+	}
+	return nil
+}
+
+// deprecate records the deprecation, returns why it was refused or "" on success
+func (r *IDRegistry) deprecate(id, replacement ErrorID, reason string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.knows(id) {
+		return "the ID is not registered in this ID registry"
+	}
+	if !r.knows(replacement) {
+		return fmt.Sprintf("replacement %s is not registered in this ID registry", replacement)
+	}
+
+	current := r.deprecations.Load()
+	ids, replacements := id.String(), replacement.String()
+	if _, exists := current.get(ids); exists {
+		return "the ID is already deprecated"
+	}
+	if ids == replacements || current.aliasOf(replacements, ids) {
+		return fmt.Sprintf("replacement %s resolves back to it", replacement)
+	}
+	if hops := current.hopsTo(ids) + 1 + current.hopsFrom(replacements); hops > maxAliasDepth {
+		return fmt.Sprintf("the replacement chain would be %d hops long, at most %d are allowed", hops, maxAliasDepth)
+	}
+
+	next := &deprecationSnapshot{
+		byID:         make(map[string]Deprecation, len(current.all())+1),
+		replacements: make(map[string]string, len(current.all())+1),
+	}
+	for k, dep := range current.all() {
+		next.byID[k] = dep
+		next.replacements[k] = current.replacements[k]
+	}
+	next.byID[ids] = Deprecation{ID: id, Replacement: replacement, Reason: reason}
+	next.replacements[ids] = replacements
+	r.deprecations.Store(next)
+	return ""
+}
+
+// GetDeprecation returns the deprecation of id on the global ID registry
+func GetDeprecation(id ErrorID) (Deprecation, bool) {
+	return globalIDRegistry.GetDeprecation(id)
+}
+
+// GetDeprecation returns the deprecation of id, false if id is not deprecated
+func (r *IDRegistry) GetDeprecation(id ErrorID) (Deprecation, bool) {
+	current := r.deprecations.Load()
+	if current == nil {
+		return Deprecation{}, false
+	}
+	return current.get(id.String())
+}
+
+// deprecationMap returns the deprecations keyed by ID string, the map must not be modified
+func (r *IDRegistry) deprecationMap() map[string]Deprecation {
+	return r.deprecations.Load().all()
+}
+
+// knows reports whether id is a trusted ID of this registry, must hold r.mu
+func (r *IDRegistry) knows(id ErrorID) bool {
+	if !id.IsRegistered() {
+		return false
+	}
+	known, ok := r.stringIndex[id.String()]
+	return ok && known.name == id.name
+}
+
+// deprecationSnapshot is an immutable view of the deprecations of an IDRegistry
+// Deprecate swaps in a new snapshot, so Is and Match read it without locking
+// A nil snapshot has no deprecations
+type deprecationSnapshot struct {
+	byID         map[string]Deprecation // ErrorID.String() -> deprecation
+	replacements map[string]string      // ErrorID.String() -> replacement ErrorID.String()
+}
+
+func (s *deprecationSnapshot) get(id string) (Deprecation, bool) {
+	if s == nil {
+		return Deprecation{}, false
+	}
+	dep, ok := s.byID[id]
+	return dep, ok
+}
+
+func (s *deprecationSnapshot) all() map[string]Deprecation {
+	if s == nil {
+		return nil
+	}
+	return s.byID
+}
+
+// aliasOf reports whether the replacement chain of from reaches to
+func (s *deprecationSnapshot) aliasOf(from, to string) bool {
+	if s == nil {
+		return false
+	}
+	for i := 0; i < maxAliasDepth; i++ {
+		next, ok := s.replacements[from]
+		if !ok {
+			return false
+		}
+		if next == to {
+			return true
+		}
+		from = next
+	}
+	return false
+}
+
+// hopsFrom returns the length of the replacement chain starting at id
+func (s *deprecationSnapshot) hopsFrom(id string) int {
+	if s == nil {
+		return 0
+	}
+	hops := 0
+	for next, ok := s.replacements[id]; ok && hops <= maxAliasDepth; next, ok = s.replacements[next] {
+		hops++
+	}
+	return hops
+}
+
+// hopsTo returns the length of the longest replacement chain ending at id
+func (s *deprecationSnapshot) hopsTo(id string) int {
+	if s == nil {
+		return 0
+	}
+	longest := 0
+	for from := range s.replacements {
+		hops := 0
+		for cur := from; cur != id && hops <= maxAliasDepth; hops++ {
+			next, ok := s.replacements[cur]
+			if !ok {
+				hops = 0
+				break
+			}
+			cur = next
+		}
+		if hops > longest {
+			longest = hops
+		}
+	}
+	return longest
+}
+
+// sameID reports whether a and b are the same ID, or one is a deprecated alias of the other
+// IDs deprecated into the same replacement don't match each other
+func sameID(a, b ErrorID) bool {
+	if a == b {
+		return true
+	}
+	as, bs := a.String(), b.String()
+	if as == bs {
+		return true
+	}
+	aliases := globalIDRegistry.deprecations.Load()
+	if aliases == nil {
+		return false
+	}
+	return aliases.aliasOf(as, bs) || aliases.aliasOf(bs, as)
+}
+
+// notifyDeprecated runs the deprecation hooks and logs when err has a deprecated ID
+func (r *Registry) notifyDeprecated(err *Error) {
+	dep, ok := globalIDRegistry.GetDeprecation(err.ID)
+	if !ok {
+		return
+	}
+
+	r.mu.RLock()
+	allowLogs := r.allowInternalLogs
+	r.mu.RUnlock()
+
+	if allowLogs {
+		log.Printf("deprecated error ID %s (%s) created, use %s (%s) instead: %s\n",
+			dep.ID, dep.ID.Name(), dep.Replacement, dep.Replacement.Name(), dep.Reason)
+	}
+	r.hooks.runDeprecated(err, dep)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/MintzyG/fail/v3"
+	failslog "github.com/MintzyG/fail/v3/plugins/logging/slog"
 )
 
 var (
@@ -14,56 +15,29 @@ var (
 	_                  = fail.Form(AdminUsernameEmpty, "username cannot be empty", false, nil)
 )
 
-type SlogLogger struct {
-	Logger          *slog.Logger
-	LogDomainErrors bool
-	LogSystemErrors bool
-	IncludeMetadata bool
-	IncludeInternal bool
-}
-
-func DefaultSlogLogger(logger *slog.Logger) *SlogLogger {
-	return &SlogLogger{
-		Logger:          logger,
-		LogDomainErrors: true,
-		LogSystemErrors: true,
-		IncludeMetadata: true,
-		IncludeInternal: true,
-	}
-}
-
-func (s *SlogLogger) Log(e *fail.Error) {
-	s.log(context.Background(), e)
-}
-
-func (s *SlogLogger) LogCtx(ctx context.Context, e *fail.Error) {
-	s.log(ctx, e)
-}
-
-func (s *SlogLogger) log(ctx context.Context, e *fail.Error) {
-	attrs := []any{
-		"error_id", e.ID.String(),
-		"is_system", e.IsSystem,
-	}
-
-	if e.IsSystem && s.LogSystemErrors {
-		s.Logger.ErrorContext(ctx, e.Message, attrs...)
-		return
-	}
-
-	if !e.IsSystem && s.LogDomainErrors {
-		s.Logger.InfoContext(ctx, e.Message, attrs...)
-	}
-}
+type requestIDKey struct{}
 
 func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	fail.SetLogger(DefaultSlogLogger(logger))
+	fail.SetLogger(failslog.New(
+		failslog.WithLogger(logger),
+		failslog.WithInternalMessage(),
+		failslog.WithContextAttrs(func(ctx context.Context) []slog.Attr {
+			if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+				return []slog.Attr{slog.String("request_id", id)}
+			}
+			return nil
+		}),
+	))
 
 	fmt.Println("=== Slog Logger Example ===")
 
 	// Use fail.New(ID) to avoid mutating the sentinel with AddMeta
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-123")
 	_ = fail.New(AdminUsernameEmpty).
 		AddMeta("user_id", 123).
-		Log()
+		LogCtx(ctx)
+
+	// *fail.Error is also a slog.LogValuer
+	logger.Info("direct", "error", fail.New(AdminUsernameEmpty))
 }
@@ -0,0 +1,153 @@
+package fail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Meta keys shown in their own section by %+v
+var formatSectionKeys = map[string]struct{}{
+	MetaValidations.Name(): {},
+	MetaTraces.Name():      {},
+	MetaDebug.Name():       {},
+}
+
+// Format implements fmt.Formatter
+//
+//	%s, %v  same as Error()
+//	%q      quoted rendered message
+//	%+v     multi-line dump: ID, name, classification, internal message, sorted meta,
+//	        validations, traces, debug entries, stack frames and the full cause chain
+//	%#v     Go syntax representation of the struct
+//
+// Example:
+//
+//	fmt.Printf("%+v\n", err)
+//	// [0_USER_0000_D] UserNotFound: user bob not found
+//	//     domain:   USER (domain error)
+//	//     level:    0
+//	//     internal: select returned no rows
+//	//     meta:
+//	//         request_id: abc
+//	//     caused by: sql: no rows in result set
+func (e *Error) Format(s fmt.State, verb rune) {
+	if e == nil {
+		_, _ = io.WriteString(s, "<nil>")
+		return
+	}
+
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			var b strings.Builder
+			e.writeVerbose(&b, "", 0)
+			_, _ = io.WriteString(s, strings.TrimSuffix(b.String(), "\n"))
+			return
+		}
+		if s.Flag('#') {
+			type plain Error // drops the Format method
+			_, _ = fmt.Fprintf(s, "%#v", (*plain)(e))
+			return
+		}
+		_, _ = io.WriteString(s, e.Error())
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.GetRendered())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(*fail.Error=%s)", verb, e.Error())
+	}
+}
+
+// writeVerbose writes the %+v dump of e, every line prefixed by indent
+func (e *Error) writeVerbose(b *strings.Builder, indent string, depth int) {
+	field := indent + "    "
+
+	fmt.Fprintf(b, "%s[%s] %s: %s\n", indent, e.ID.String(), e.ID.Name(), e.GetRendered())
+
+	kind := "domain error"
+	if e.IsSystem {
+		kind = "system error"
+	}
+	fmt.Fprintf(b, "%sdomain:   %s (%s)\n", field, e.ID.Domain(), kind)
+	fmt.Fprintf(b, "%slevel:    %d\n", field, e.ID.Level())
+	if e.InternalMessage != "" {
+		fmt.Fprintf(b, "%sinternal: %s\n", field, e.InternalMessage)
+	}
+	if !e.isRegistered {
+		fmt.Fprintf(b, "%sregistered: false\n", field)
+	}
+
+	keys := make([]string, 0, len(e.Meta))
+	for k := range e.Meta {
+		if _, section := formatSectionKeys[k]; !section {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		fmt.Fprintf(b, "%smeta:\n", field)
+		for _, k := range keys {
+			fmt.Fprintf(b, "%s    %s: %v\n", field, k, e.Meta[k])
+		}
+	}
+
+	if validations, ok := getMeta(e, MetaValidations); ok && len(validations) > 0 {
+		fmt.Fprintf(b, "%svalidations:\n", field)
+		for _, v := range validations {
+			fmt.Fprintf(b, "%s    %s: %s\n", field, v.Field, v.Message)
+		}
+	}
+	writeVerboseList(b, field, "traces", e, MetaTraces)
+	writeVerboseList(b, field, "debug", e, MetaDebug)
+
+	if frames := e.StackTrace(); len(frames) > 0 {
+		fmt.Fprintf(b, "%sstack:\n", field)
+		for _, f := range frames {
+			fmt.Fprintf(b, "%s    %s\n%s        %s:%d\n", field, f.Function, field, f.File, f.Line)
+		}
+	}
+
+	writeVerboseCause(b, field, e.Cause, depth+1)
+}
+
+// writeVerboseCause walks the cause chain, *Error causes get their own dump
+func writeVerboseCause(b *strings.Builder, indent string, cause error, depth int) {
+	if cause == nil {
+		return
+	}
+	if depth > maxCauseDepth {
+		fmt.Fprintf(b, "%scaused by: ...\n", indent)
+		return
+	}
+
+	if e, ok := cause.(*Error); ok {
+		fmt.Fprintf(b, "%scaused by:\n", indent)
+		e.writeVerbose(b, indent+"    ", depth)
+		return
+	}
+
+	fmt.Fprintf(b, "%scaused by: %s\n", indent, cause.Error())
+	switch u := cause.(type) {
+	case interface{ Unwrap() []error }:
+		for _, inner := range u.Unwrap() {
+			writeVerboseCause(b, indent+"    ", inner, depth+1)
+		}
+	default:
+		writeVerboseCause(b, indent, errors.Unwrap(cause), depth+1)
+	}
+}
+
+func writeVerboseList(b *strings.Builder, indent, title string, e *Error, key MetaKey[[]string]) {
+	list, ok := getMeta(e, key)
+	if !ok || len(list) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s%s:\n", indent, title)
+	for _, item := range list {
+		fmt.Fprintf(b, "%s    - %s\n", indent, item)
+	}
+}
@@ -0,0 +1,115 @@
+package fail
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Hedge runs fn and, if it hasn't returned after delay, launches another attempt in
+// parallel, up to attempts in total. A failed attempt launches the next one immediately.
+// The first success is returned and every other attempt is cancelled via ctx.
+//
+// Only use Hedge for idempotent calls, several attempts may run at the same time.
+//
+// Domain errors (see IsDomain) are answers rather than failures, the first one is
+// returned as-is and the remaining attempts are cancelled. If every attempt fails,
+// their errors are aggregated through an ErrorGroup (see ErrorGroup.ToError) whose
+// cause joins the attempt errors, so errors.Is and errors.As reach every one of them.
+// A single failed attempt returns its error as-is.
+// If ctx is done before an attempt succeeds a RetryAborted error is returned, even
+// when the cancelled attempts report their own errors first.
+//
+// Example:
+//
+//	user, err := fail.Hedge(ctx, 3, 50*time.Millisecond, func(ctx context.Context) (User, error) {
+//		return replica.GetUser(ctx, id)
+//	})
+func Hedge[T any](ctx context.Context, attempts int, delay time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	// Buffered so losing attempts never block after we returned
+	results := make(chan result, attempts)
+	launched := 0
+	launch := func() {
+		launched++
+		go func() {
+			v, err := fn(ctx)
+			results <- result{value: v, err: err}
+		}()
+	}
+
+	group := NewErrorGroup(attempts)
+	var errs []error // attempt errors as returned, ErrorGroup converts them to *Error
+	collect := func(err error) {
+		group.Add(err)
+		errs = append(errs, err)
+	}
+	failed := func() error {
+		switch len(errs) {
+		case 0:
+			return nil
+		case 1:
+			return errs[0]
+		}
+		return group.ToError().With(errors.Join(errs...))
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	// Cancelling parent also cancels the attempts, their ctx errors race parent.Done()
+	// so every path checks parent first to always report RetryAborted
+	aborted := func() (T, error) {
+		return zero, retryAborted(parent.Err(), failed(), launched, nil)
+	}
+
+	launch()
+	for finished := 0; finished < launched; {
+		select {
+		case r := <-results:
+			finished++
+			if r.err == nil {
+				return r.value, nil
+			}
+			if parent.Err() != nil {
+				collect(r.err)
+				return aborted()
+			}
+			if IsDomain(r.err) {
+				return zero, r.err
+			}
+			collect(r.err)
+			if launched < attempts {
+				launch()
+				timer.Reset(delay)
+			}
+
+		case <-timer.C:
+			if launched < attempts {
+				launch()
+				timer.Reset(delay)
+			}
+
+		case <-parent.Done():
+			return aborted()
+		}
+	}
+
+	if parent.Err() != nil {
+		return aborted()
+	}
+	return zero, failed()
+}
@@ -3,6 +3,7 @@ package fail
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Must panics if the error is not nil
@@ -23,14 +24,62 @@ func MustNew(id ErrorID) *Error {
 }
 
 // Is checks if the target error is an Error with the specified ID
+// A deprecated ID and its replacement match each other, see Deprecate
 func Is(err error, id ErrorID) bool {
 	var e *Error
 	if errors.As(err, &e) {
-		return e.ID.String() == id.String()
+		return sameID(e.ID, id)
 	}
 	return false
 }
 
+// Is implements errors.Is support, e matches target if target is a *Error or an
+// IDError with the same trusted ID, even when they are distinct instances
+// A deprecated ID and its replacement match each other, see Deprecate
+//
+// Example:
+//
+//	var ErrUserNotFound = fail.Form(UserNotFound, "user not found", false, nil)
+//
+//	err := fmt.Errorf("handler: %w", fail.New(UserNotFound))
+//	errors.Is(err, ErrUserNotFound)            // true
+//	errors.Is(err, fail.IDError(UserNotFound)) // true
+func (e *Error) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+
+	var id ErrorID
+	switch t := target.(type) {
+	case *Error:
+		if t == nil {
+			return false
+		}
+		id = t.ID
+	case idError:
+		id = t.id
+	default:
+		return false
+	}
+
+	return e.ID.IsRegistered() && id.IsRegistered() && sameID(e.ID, id)
+}
+
+// idError is the errors.Is target returned by IDError
+type idError struct {
+	id ErrorID
+}
+
+func (t idError) Error() string {
+	return fmt.Sprintf("fail: error with ID(%s)", t.id)
+}
+
+// IDError returns a lightweight target for errors.Is that matches any *Error with id
+// Useful for code that only knows the standard errors package
+func IDError(id ErrorID) error {
+	return idError{id: id}
+}
+
 // As extracts an Error from any error
 func As(err error) (*Error, bool) {
 	var e *Error
@@ -124,32 +173,22 @@ func GetMeta(err error, key string) (any, bool) {
 	return nil, false
 }
 
+// GetRetryAfter extracts the retry_after hint set by (*Error).RetryAfter from an error
+func GetRetryAfter(err error) (time.Duration, bool) {
+	return GetMetaAs(err, MetaRetryAfter)
+}
+
 // GetValidations extracts validation errors from an error
 func GetValidations(err error) ([]ValidationError, bool) {
-	if e, ok := As(err); ok && e.Meta != nil {
-		if validations, ok := e.Meta["validations"].([]ValidationError); ok {
-			return validations, true
-		}
-	}
-	return nil, false
+	return GetMetaAs(err, MetaValidations)
 }
 
 // GetTraces extracts trace information from an error
 func GetTraces(err error) ([]string, bool) {
-	if e, ok := As(err); ok && e.Meta != nil {
-		if traces, ok := e.Meta["traces"].([]string); ok {
-			return traces, true
-		}
-	}
-	return nil, false
+	return GetMetaAs(err, MetaTraces)
 }
 
 // GetDebug extracts debug information from an error
 func GetDebug(err error) ([]string, bool) {
-	if e, ok := As(err); ok && e.Meta != nil {
-		if debug, ok := e.Meta["debug"].([]string); ok {
-			return debug, true
-		}
-	}
-	return nil, false
+	return GetMetaAs(err, MetaDebug)
 }
@@ -5,6 +5,7 @@ import (
 	"log"
 	"runtime"
 	"sync"
+	"time"
 )
 
 type HookType int
@@ -19,6 +20,12 @@ const (
 	HookFromSuccess
 	HookForm
 	HookTranslate
+	HookMatch
+	HookCircuitStateChange
+	HookRetry
+	HookRetrySuccess
+	HookRetryExhausted
+	HookDeprecated
 )
 
 // Hooks manages lifecycle callbacks for errors
@@ -35,6 +42,12 @@ type Hooks struct {
 	onForm        []func(ErrorID, *Error)
 	onTranslate   []func(*Error, map[string]any)
 	onMatch       []func(*Error, map[string]any)
+
+	onCircuitStateChange []func(string, CircuitState, CircuitState)
+	onRetry              []func(*Error, int, time.Duration)
+	onRetrySuccess       []func(*Error, int, time.Duration)
+	onRetryExhausted     []func(*Error, int, time.Duration)
+	onDeprecated         []func(*Error, Deprecation)
 }
 
 // Frame represents a single stack frame for error traces
@@ -161,6 +174,60 @@ func (h *Hooks) On(t HookType, fn any) {
 		h.onTranslate = append(h.onTranslate, f)
 		h.mu.Unlock()
 
+	case HookMatch:
+		f, ok := fn.(func(*Error, map[string]any))
+		if !ok {
+			panic(fmt.Sprintf("HookMatch requires func(*Error, map[string]any), got %T", fn))
+		}
+		h.mu.Lock()
+		h.onMatch = append(h.onMatch, f)
+		h.mu.Unlock()
+
+	case HookCircuitStateChange:
+		f, ok := fn.(func(string, CircuitState, CircuitState))
+		if !ok {
+			panic(fmt.Sprintf("HookCircuitStateChange requires func(string, CircuitState, CircuitState), got %T", fn))
+		}
+		h.mu.Lock()
+		h.onCircuitStateChange = append(h.onCircuitStateChange, f)
+		h.mu.Unlock()
+
+	case HookRetry:
+		f, ok := fn.(func(*Error, int, time.Duration))
+		if !ok {
+			panic(fmt.Sprintf("HookRetry requires func(*Error, int, time.Duration), got %T", fn))
+		}
+		h.mu.Lock()
+		h.onRetry = append(h.onRetry, f)
+		h.mu.Unlock()
+
+	case HookRetrySuccess:
+		f, ok := fn.(func(*Error, int, time.Duration))
+		if !ok {
+			panic(fmt.Sprintf("HookRetrySuccess requires func(*Error, int, time.Duration), got %T", fn))
+		}
+		h.mu.Lock()
+		h.onRetrySuccess = append(h.onRetrySuccess, f)
+		h.mu.Unlock()
+
+	case HookRetryExhausted:
+		f, ok := fn.(func(*Error, int, time.Duration))
+		if !ok {
+			panic(fmt.Sprintf("HookRetryExhausted requires func(*Error, int, time.Duration), got %T", fn))
+		}
+		h.mu.Lock()
+		h.onRetryExhausted = append(h.onRetryExhausted, f)
+		h.mu.Unlock()
+
+	case HookDeprecated:
+		f, ok := fn.(func(*Error, Deprecation))
+		if !ok {
+			panic(fmt.Sprintf("HookDeprecated requires func(*Error, Deprecation), got %T", fn))
+		}
+		h.mu.Lock()
+		h.onDeprecated = append(h.onDeprecated, f)
+		h.mu.Unlock()
+
 	default:
 		panic(fmt.Sprintf("unknown hook type: %d", t))
 	}
@@ -261,6 +328,60 @@ func (h *Hooks) runTranslate(err *Error, data map[string]any) {
 	})
 }
 
+func (h *Hooks) runMatch(err *Error, data map[string]any) {
+	h.mu.RLock()
+	hooks := h.onMatch
+	h.mu.RUnlock()
+	executeHooks(hooks, func(fn func(*Error, map[string]any)) {
+		fn(err, data)
+	})
+}
+
+func (h *Hooks) runCircuitStateChange(name string, from, to CircuitState) {
+	h.mu.RLock()
+	hooks := h.onCircuitStateChange
+	h.mu.RUnlock()
+	executeHooks(hooks, func(fn func(string, CircuitState, CircuitState)) {
+		fn(name, from, to)
+	})
+}
+
+func (h *Hooks) runRetry(err *Error, attempt int, delay time.Duration) {
+	h.mu.RLock()
+	hooks := h.onRetry
+	h.mu.RUnlock()
+	executeHooks(hooks, func(fn func(*Error, int, time.Duration)) {
+		fn(err, attempt, delay)
+	})
+}
+
+func (h *Hooks) runRetrySuccess(err *Error, attempt int, delay time.Duration) {
+	h.mu.RLock()
+	hooks := h.onRetrySuccess
+	h.mu.RUnlock()
+	executeHooks(hooks, func(fn func(*Error, int, time.Duration)) {
+		fn(err, attempt, delay)
+	})
+}
+
+func (h *Hooks) runRetryExhausted(err *Error, attempt int, delay time.Duration) {
+	h.mu.RLock()
+	hooks := h.onRetryExhausted
+	h.mu.RUnlock()
+	executeHooks(hooks, func(fn func(*Error, int, time.Duration)) {
+		fn(err, attempt, delay)
+	})
+}
+
+func (h *Hooks) runDeprecated(err *Error, dep Deprecation) {
+	h.mu.RLock()
+	hooks := h.onDeprecated
+	h.mu.RUnlock()
+	executeHooks(hooks, func(fn func(*Error, Deprecation)) {
+		fn(err, dep)
+	})
+}
+
 // IDE-friendly convenience wrappers
 
 func OnCreate(fn func(*Error, map[string]any))    { On(HookCreate, fn) }
@@ -272,3 +393,21 @@ func OnFromFail(fn func(error))                   { On(HookFromFail, fn) }
 func OnFromSuccess(fn func(error, *Error))        { On(HookFromSuccess, fn) }
 func OnForm(fn func(ErrorID, *Error))             { On(HookForm, fn) }
 func OnTranslate(fn func(*Error, map[string]any)) { On(HookTranslate, fn) }
+func OnMatch(fn func(*Error, map[string]any))     { On(HookMatch, fn) }
+
+func OnCircuitStateChange(fn func(string, CircuitState, CircuitState)) {
+	On(HookCircuitStateChange, fn)
+}
+
+// OnRetry is called when an attempt failed and will be retried after delay
+func OnRetry(fn func(*Error, int, time.Duration)) { On(HookRetry, fn) }
+
+// OnRetrySuccess is called when an attempt succeeded after previous failures
+// It receives the last retried error, the successful attempt number and the last delay
+func OnRetrySuccess(fn func(*Error, int, time.Duration)) { On(HookRetrySuccess, fn) }
+
+// OnRetryExhausted is called when retrying stops because attempts, MaxElapsed or the budget ran out
+func OnRetryExhausted(fn func(*Error, int, time.Duration)) { On(HookRetryExhausted, fn) }
+
+// OnDeprecated is called when New creates an error with a deprecated ID, see Deprecate
+func OnDeprecated(fn func(*Error, Deprecation)) { On(HookDeprecated, fn) }
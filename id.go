@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // reservedDomain is the domain reserved for internal error IDs.
@@ -66,6 +69,78 @@ func (id ErrorID) IsRegistered() bool {
 	return id.isRegistered
 }
 
+// MarshalText implements encoding.TextMarshaler using the String() format
+func (id ErrorID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+// The result is trusted only if the value is registered in the global ID registry,
+// a well-formed but unknown value yields an untrusted ID (IsRegistered() == false).
+// Empty text yields the zero ErrorID.
+//
+// Returns an IDMalformed error if text isn't in the LEVEL_DOMAIN_NUM_TYPE format
+func (id *ErrorID) UnmarshalText(text []byte) error {
+	s := string(text)
+	if s == "" {
+		*id = ErrorID{}
+		return nil
+	}
+
+	if trusted, ok := globalIDRegistry.Lookup(s); ok {
+		*id = trusted
+		return nil
+	}
+
+	parsed, ok := parseErrorID(s)
+	if !ok {
+		return New(IDMalformed).WithArgs(s).Render()
+	}
+	*id = parsed
+	return nil
+}
+
+// parseErrorID parses the String() format into an untrusted ErrorID without a name
+// The domain may contain underscores, level and number are the outer segments
+func parseErrorID(s string) (ErrorID, bool) {
+	parts := strings.Split(s, "_")
+	if len(parts) < 4 {
+		return ErrorID{}, false
+	}
+
+	level, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return ErrorID{}, false
+	}
+
+	number, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil || number < 0 {
+		return ErrorID{}, false
+	}
+
+	var static bool
+	switch parts[len(parts)-1] {
+	case "S":
+		static = true
+	case "D":
+		static = false
+	default:
+		return ErrorID{}, false
+	}
+
+	domain := strings.Join(parts[1:len(parts)-2], "_")
+	if domain == "" {
+		return ErrorID{}, false
+	}
+
+	return ErrorID{
+		domain:   domain,
+		level:    level,
+		isStatic: static,
+		number:   number,
+	}, true
+}
+
 // OverrideAllowIDRuntimePanics sets global id registry override
 func OverrideAllowIDRuntimePanics(allow bool) {
 	globalIDRegistry.OverrideAllowRuntimePanics(allow)
@@ -95,8 +170,10 @@ type numberNode struct {
 // Numbers are explicitly assigned per domain and type (static/dynamic)
 type IDRegistry struct {
 	mu                       sync.Mutex
-	registeredIDs            map[string]ErrorID    // name -> ErrorID
-	numberIndex              map[string]*list.List // "domain:static" -> sorted list of numberNode
+	registeredIDs            map[string]ErrorID                  // name -> ErrorID
+	stringIndex              map[string]ErrorID                  // ErrorID.String() -> ErrorID
+	numberIndex              map[string]*list.List               // "domain:static" -> sorted list of numberNode
+	deprecations             atomic.Pointer[deprecationSnapshot] // see Deprecate, read without locking
 	allowRuntimePanics       *bool
 	allowRuntimeRegistration bool
 }
@@ -104,6 +181,7 @@ type IDRegistry struct {
 // Global ID registry
 var globalIDRegistry = &IDRegistry{
 	registeredIDs: make(map[string]ErrorID),
+	stringIndex:   make(map[string]ErrorID),
 	numberIndex:   make(map[string]*list.List),
 }
 
@@ -334,6 +412,7 @@ func (r *IDRegistry) ID(level int, domain string, number int, static bool, name
 	}
 
 	r.registeredIDs[name] = id
+	r.stringIndex[id.String()] = id
 	return id
 }
 
@@ -454,6 +533,7 @@ func (r *IDRegistry) internalID(level, number int, static bool, name string) Err
 	}
 
 	r.registeredIDs[name] = id
+	r.stringIndex[id.String()] = id
 	return id
 }
 
@@ -462,7 +542,35 @@ func (r *IDRegistry) Reset() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.registeredIDs = make(map[string]ErrorID)
+	r.stringIndex = make(map[string]ErrorID)
 	r.numberIndex = make(map[string]*list.List)
+	r.deprecations.Store(nil)
+}
+
+// Lookup returns the trusted ErrorID whose String() is s (e.g., "0_AUTH_0042_S")
+func (r *IDRegistry) Lookup(s string) (ErrorID, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, ok := r.stringIndex[s]
+	return id, ok
+}
+
+// LookupByName returns the trusted ErrorID registered under name (e.g., "AuthInvalidCredentials")
+func (r *IDRegistry) LookupByName(name string) (ErrorID, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, ok := r.registeredIDs[name]
+	return id, ok
+}
+
+// LookupID returns the trusted ErrorID whose String() is s from the global ID registry
+func LookupID(s string) (ErrorID, bool) {
+	return globalIDRegistry.Lookup(s)
+}
+
+// LookupIDByName returns the trusted ErrorID registered under name in the global ID registry
+func LookupIDByName(name string) (ErrorID, bool) {
+	return globalIDRegistry.LookupByName(name)
 }
 
 // GetAllIDs returns all registered error IDs sorted by domain, type, then number
@@ -493,6 +601,7 @@ func (r *IDRegistry) GetAllIDs() []ErrorID {
 func NewIDRegistry() *IDRegistry {
 	return &IDRegistry{
 		registeredIDs: make(map[string]ErrorID),
+		stringIndex:   make(map[string]ErrorID),
 		numberIndex:   make(map[string]*list.List),
 	}
 }
@@ -586,11 +695,15 @@ func (r *IDRegistry) validateNoGaps() {
 			continue
 		}
 
-		var domain string
-		var static bool
-		_, err := fmt.Sscanf(groupKey, "%s:%v", &domain, &static)
+		// Deprecated IDs stay in the index, their numbers keep counting
+		sep := strings.LastIndex(groupKey, ":")
+		if sep < 0 {
+			panic(fmt.Sprintf("[fail]: invalid group key %q", groupKey))
+		}
+		domain := groupKey[:sep]
+		static, err := strconv.ParseBool(groupKey[sep+1:])
 		if err != nil {
-			panic(fmt.Sprintf("[fail]: invalid group key, error while scanning: %v", err))
+			panic(fmt.Sprintf("[fail]: invalid group key %q: %v", groupKey, err))
 		}
 
 		expected := 0
@@ -608,6 +721,36 @@ func (r *IDRegistry) validateNoGaps() {
 	}
 }
 
+// IDListEntry is an entry of the ExportIDList output
+type IDListEntry struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain"`
+	Static bool   `json:"static"`
+	Level  int    `json:"level"`
+	Number int    `json:"number"`
+	ID     string `json:"id"`
+
+	Status            string `json:"status"`                       // IDStatusActive or IDStatusDeprecated
+	ReplacedBy        string `json:"replaced_by,omitempty"`        // ID of the replacement, when deprecated
+	DeprecationReason string `json:"deprecation_reason,omitempty"` // See Deprecate
+}
+
+// setStatus fills the deprecation fields from deprecations (keyed by ID string)
+func (e *IDListEntry) setStatus(deprecations map[string]Deprecation) {
+	e.Status = IDStatusActive
+	if dep, ok := deprecations[e.ID]; ok {
+		e.Status = IDStatusDeprecated
+		e.ReplacedBy = dep.Replacement.String()
+		e.DeprecationReason = dep.Reason
+	}
+}
+
+// ID statuses reported by ExportIDList
+const (
+	IDStatusActive     = "active"
+	IDStatusDeprecated = "deprecated"
+)
+
 // ExportIDList returns all registered error IDs as JSON bytes
 func ExportIDList() ([]byte, error) {
 	return globalIDRegistry.ExportIDList()
@@ -618,15 +761,6 @@ func (r *IDRegistry) ExportIDList() ([]byte, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	type exportEntry struct {
-		Name   string `json:"name"`
-		Domain string `json:"domain"`
-		Static bool   `json:"static"`
-		Level  int    `json:"level"`
-		Number int    `json:"number"`
-		ID     string `json:"id"`
-	}
-
 	// Collect IDs
 	ids := make([]ErrorID, 0, len(r.registeredIDs))
 	for _, id := range r.registeredIDs {
@@ -644,9 +778,10 @@ func (r *IDRegistry) ExportIDList() ([]byte, error) {
 		return ids[i].number < ids[j].number
 	})
 
-	entries := make([]exportEntry, len(ids))
+	deprecations := r.deprecationMap()
+	entries := make([]IDListEntry, len(ids))
 	for i, id := range ids {
-		entries[i] = exportEntry{
+		entries[i] = IDListEntry{
 			Name:   id.name,
 			Domain: id.domain,
 			Static: id.isStatic,
@@ -654,6 +789,7 @@ func (r *IDRegistry) ExportIDList() ([]byte, error) {
 			Number: id.number,
 			ID:     id.String(),
 		}
+		entries[i].setStatus(deprecations)
 	}
 
 	return json.MarshalIndent(entries, "", "  ")
@@ -17,6 +17,14 @@ var (
 	UnregisteredIDError         = internalID(9, 12, false, "FailIDNotRegisteredError")
 	RegisterManyError           = internalID(9, 13, false, "FailRegisterManyError")
 	RegistryAlreadyRegistered   = internalID(9, 14, false, "FailRegistryAlreadyRegistered")
+	RetryConfigInvalid          = internalID(9, 15, false, "FailRetryConfigInvalid")
+	RetryAborted                = internalID(0, 16, false, "FailRetryAborted")
+	RetryExhausted              = internalID(0, 17, false, "FailRetryExhausted")
+	CircuitOpen                 = internalID(0, 18, false, "FailCircuitOpen")
+	ErrorPayloadInvalid         = internalID(0, 19, false, "FailErrorPayloadInvalid")
+	IDMalformed                 = internalID(0, 20, false, "FailIDMalformed")
+	CatalogInvalid              = internalID(0, 21, false, "FailCatalogInvalid")
+	IDDeprecationInvalid        = internalID(0, 22, false, "FailIDDeprecationInvalid")
 
 	TranslatorNil       = internalID(0, 0, true, "FailTranslatorNil")
 	TranslatorNameEmpty = internalID(0, 1, true, "FailTranslatorNameEmpty")
@@ -43,4 +51,12 @@ var (
 	errUnregisteredIDError         = Form(UnregisteredIDError, "ID(%s) is not registered in the ID registry", true, nil, "UNSET ID")
 	errRegisterManyError           = Form(RegisterManyError, "one or more errors occurred during error registering", true, nil)
 	errRegistryAlreadyRegistered   = Form(RegistryAlreadyRegistered, "%s registry already registered", true, nil, "UNSET REGISTRY NAME")
+	errRetryConfigInvalid          = Form(RetryConfigInvalid, "invalid retry config: %s", true, nil, "UNSET REASON")
+	errRetryAborted                = Form(RetryAborted, "retry aborted after %d attempt(s)", false, nil, 0)
+	errRetryExhausted              = Form(RetryExhausted, "retry exhausted after %d attempt(s)", false, nil, 0)
+	errCircuitOpen                 = Form(CircuitOpen, "circuit %s is open", true, nil, "UNSET CIRCUIT NAME")
+	errErrorPayloadInvalid         = Form(ErrorPayloadInvalid, "invalid error payload: %s", false, nil, "UNSET REASON")
+	errIDMalformed                 = Form(IDMalformed, "malformed error ID %q", false, nil, "UNSET ID")
+	errCatalogInvalid              = Form(CatalogInvalid, "invalid ID catalog: %s", false, nil, "UNSET REASON")
+	errIDDeprecationInvalid        = Form(IDDeprecationInvalid, "cannot deprecate %s: %s", false, nil, "UNSET ID", "UNSET REASON")
 )
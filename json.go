@@ -0,0 +1,259 @@
+package fail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrorJSONVersion is the version of the JSON wire schema produced by (*Error).MarshalJSON
+const ErrorJSONVersion = 1
+
+// maxCauseDepth bounds serialized cause chains to protect against cycles
+const maxCauseDepth = 32
+
+// errorJSON is the stable wire schema of an *Error
+// Non-fail causes only carry their message
+type errorJSON struct {
+	Version  int            `json:"v,omitempty"`
+	ID       string         `json:"id,omitempty"`
+	Name     string         `json:"name,omitempty"`
+	Domain   string         `json:"domain,omitempty"`
+	Level    int            `json:"level"`
+	Number   int            `json:"number"`
+	Static   bool           `json:"static"`
+	System   bool           `json:"system"`
+	Message  string         `json:"message"`
+	Rendered string         `json:"rendered,omitempty"`
+	Args     []any          `json:"args,omitempty"`
+	Locale   string         `json:"locale,omitempty"`
+	Meta     map[string]any `json:"meta,omitempty"`
+	Cause    *errorJSON     `json:"cause,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler using a versioned, stable schema
+// The internal message is never included, values in Args and Meta that can't be
+// marshaled are replaced by their fmt representation
+//
+// Example output:
+//
+//	{"v":1,"id":"0_USER_0000_S","name":"UserNotFound","domain":"USER","level":0,"number":0,
+//	 "static":true,"system":false,"message":"user %s not found","rendered":"user bob not found",
+//	 "args":["bob"],"cause":{"message":"sql: no rows in result set"}}
+func (e *Error) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	payload := toErrorJSON(e, 0)
+	payload.Version = ErrorJSONVersion
+	return json.Marshal(payload)
+}
+
+// UnmarshalJSON implements json.Unmarshaler by re-hydrating through the global registry
+// See Registry.UnmarshalError for details
+func (e *Error) UnmarshalJSON(data []byte) error {
+	hydrated, err := global.UnmarshalError(data)
+	if err != nil {
+		return err
+	}
+	*e = *hydrated
+	return nil
+}
+
+// UnmarshalError re-hydrates a JSON payload produced by MarshalJSON using the global registry
+func UnmarshalError(data []byte) (*Error, error) {
+	return global.UnmarshalError(data)
+}
+
+// UnmarshalError re-hydrates a JSON payload produced by MarshalJSON into an *Error
+//
+// If the ID is registered in this registry the result is a registered *Error from this
+// registry, otherwise the result is untrusted (IsRegistered() == false) and can't be
+// translated. The cause chain is re-hydrated recursively, non-fail causes keep
+// their message and still unwrap to the rest of the chain. Library meta keys get
+// their Go type back (time.Duration, RetryHistory, ...), so GetRetryAfter,
+// IsRetryableDefault and GetRetryHistory work on decoded errors.
+//
+// Returns an ErrorPayloadInvalid error if the payload can't be decoded
+func (r *Registry) UnmarshalError(data []byte) (*Error, error) {
+	var payload errorJSON
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, New(ErrorPayloadInvalid).WithArgs("malformed json").With(err).Render()
+	}
+
+	if payload.Version > ErrorJSONVersion {
+		return nil, New(ErrorPayloadInvalid).
+			WithArgs(fmt.Sprintf("unsupported version %d", payload.Version)).
+			AddMeta("version", payload.Version).
+			Render()
+	}
+
+	if payload.ID == "" {
+		return nil, New(ErrorPayloadInvalid).WithArgs("missing id").Render()
+	}
+
+	return r.hydrate(&payload, 0), nil
+}
+
+func toErrorJSON(err error, depth int) *errorJSON {
+	if err == nil || depth > maxCauseDepth {
+		return nil
+	}
+
+	e, ok := err.(*Error)
+	if !ok {
+		// Keep walking the chain, a *Error may be wrapped by a generic error
+		return &errorJSON{
+			Message: err.Error(),
+			Cause:   toErrorJSON(errors.Unwrap(err), depth+1),
+		}
+	}
+
+	return &errorJSON{
+		ID:       e.ID.String(),
+		Name:     e.ID.Name(),
+		Domain:   e.ID.Domain(),
+		Level:    e.ID.Level(),
+		Number:   e.ID.Number(),
+		Static:   e.ID.IsStatic(),
+		System:   e.IsSystem,
+		Message:  e.Message,
+		Rendered: e.GetRendered(),
+		Args:     jsonSafeSlice(e.Args),
+		Locale:   e.Locale,
+		Meta:     jsonSafeMap(e.Meta),
+		Cause:    toErrorJSON(e.Cause, depth+1),
+	}
+}
+
+func (r *Registry) fromErrorJSON(p *errorJSON, depth int) error {
+	if p == nil || depth > maxCauseDepth {
+		return nil
+	}
+
+	if p.ID == "" {
+		return &remoteError{
+			message: p.Message,
+			cause:   r.fromErrorJSON(p.Cause, depth+1),
+		}
+	}
+
+	return r.hydrate(p, depth)
+}
+
+// remoteError stands in for a non-fail cause after a JSON round trip
+// It keeps the original message and the rest of the chain reachable through Unwrap
+type remoteError struct {
+	message string
+	cause   error
+}
+
+func (e *remoteError) Error() string { return e.message }
+func (e *remoteError) Unwrap() error { return e.cause }
+
+// hydrate builds the *Error for a payload with an ID
+// Fields are set directly since this is a reconstruction, not a mutation
+func (r *Registry) hydrate(p *errorJSON, depth int) *Error {
+	r.mu.RLock()
+	tmpl, known := r.errors[p.ID]
+	r.mu.RUnlock()
+
+	var e *Error
+	if known {
+		e = r.New(tmpl.ID)
+		// Static messages are owned by the local definition
+		if !e.isStatic {
+			e.Message = p.Message
+		}
+	} else {
+		e = &Error{
+			ID: ErrorID{
+				name:     p.Name,
+				domain:   p.Domain,
+				level:    p.Level,
+				isStatic: p.Static,
+				number:   p.Number,
+			},
+			Message:  p.Message,
+			registry: r,
+			isStatic: p.Static,
+		}
+	}
+
+	e.IsSystem = p.System
+	e.Args = p.Args
+	e.Locale = p.Locale
+	if len(p.Meta) > 0 {
+		if e.Meta == nil {
+			e.Meta = make(map[string]any, len(p.Meta))
+		}
+		for k, v := range p.Meta {
+			e.Meta[k] = decodeMetaValue(k, v)
+		}
+	}
+
+	if cause := r.fromErrorJSON(p.Cause, depth+1); cause != nil {
+		e.Cause = cause
+	}
+
+	return e
+}
+
+// decodeMetaValue restores the Go type of a library key decoded from the wire
+// (JSON numbers, objects and arrays, or codec integers) so typed accessors like
+// GetRetryAfter and GetRetryHistory keep working. Any other key, or a value that
+// doesn't convert (e.g. a user value under a library name), is returned as is
+func decodeMetaValue(key string, value any) any {
+	typ := libraryMetaTypes[key]
+	if typ == nil || value == nil || reflect.TypeOf(value) == typ {
+		return value
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	restored := reflect.New(typ)
+	if err := json.Unmarshal(data, restored.Interface()); err != nil {
+		return value
+	}
+	return restored.Elem().Interface()
+}
+
+func jsonSafeSlice(values []any) []any {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = jsonSafeValue(v)
+	}
+	return out
+}
+
+func jsonSafeMap(values map[string]any) map[string]any {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(values))
+	for k, v := range values {
+		out[k] = jsonSafeValue(v)
+	}
+	return out
+}
+
+// jsonSafeValue returns v if it can be marshaled, its fmt representation otherwise
+func jsonSafeValue(v any) any {
+	if err, ok := v.(error); ok {
+		switch err.(type) {
+		case *Error, RetryHistory: // marshaled with their own schema
+		default:
+			return err.Error()
+		}
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return v
+}
@@ -54,6 +54,7 @@ func (r *Registry) SetDefaultLocale(locale string) {
 // Localize resolves the translated message template for this error's locale
 // Stores result in e.Message, returns *Error for chaining
 func (e *Error) Localize() *Error {
+	e = e.unshare()
 	locale := e.resolveLocale()
 	e.Message = resolveTemplate(e, locale)
 	return e
@@ -62,6 +63,8 @@ func (e *Error) Localize() *Error {
 // Render formats the error's message template with its arguments
 // Stores result in e.Message, returns *Error for chaining
 func (e *Error) Render() *Error {
+	e = e.unshare()
+
 	// Ensure we have localized template first
 	if e.Message == "" {
 		_ = e.Localize()
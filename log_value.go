@@ -0,0 +1,68 @@
+package fail
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// LogValue implements slog.LogValuer so errors log as a structured group
+//
+// Example:
+//
+//	slog.Error("request failed", "error", err)
+//	// error.id=0_USER_0000_D error.domain=USER error.level=0 error.system=false
+//	// error.message="user bob not found" error.meta.request_id=abc error.cause="sql: no rows in result set"
+func (e *Error) LogValue() slog.Value {
+	if e == nil {
+		return slog.AnyValue(nil)
+	}
+	return slog.GroupValue(e.logAttrs(nil, 0)...)
+}
+
+// logAttrs builds the LogValue attributes, meta keys are sorted and filtered by keep (nil keeps all)
+func (e *Error) logAttrs(keep func(string) bool, depth int) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("id", e.ID.String()),
+		slog.String("domain", e.ID.Domain()),
+		slog.Int("level", e.ID.Level()),
+		slog.Bool("system", e.IsSystem),
+		slog.String("message", e.GetRendered()),
+	}
+
+	if len(e.Meta) > 0 {
+		keys := make([]string, 0, len(e.Meta))
+		for k := range e.Meta {
+			if keep == nil || keep(k) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		meta := make([]any, 0, len(keys))
+		for _, k := range keys {
+			meta = append(meta, slog.Any(k, e.Meta[k]))
+		}
+		if len(meta) > 0 {
+			attrs = append(attrs, slog.Group("meta", meta...))
+		}
+	}
+
+	if e.Cause != nil {
+		if cause, ok := e.Cause.(*Error); ok && depth < maxCauseDepth {
+			attrs = append(attrs, slog.Attr{Key: "cause", Value: slog.GroupValue(cause.logAttrs(keep, depth+1)...)})
+		} else {
+			attrs = append(attrs, slog.String("cause", e.Cause.Error()))
+		}
+	}
+
+	return attrs
+}
+
+// LogAttrs returns the attributes of LogValue, keeping only the meta keys accepted by keep (nil keeps all)
+// Useful for logger adapters that need to filter meta
+func (e *Error) LogAttrs(keep func(key string) bool) []slog.Attr {
+	if e == nil {
+		return nil
+	}
+	return e.logAttrs(keep, 0)
+}
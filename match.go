@@ -0,0 +1,205 @@
+package fail
+
+// ErrorMatcher provides first-match-wins pattern matching over errors
+// Any error is accepted, it is normalized to *Error via As() or From() on entry
+type ErrorMatcher struct {
+	err     error
+	fe      *Error
+	matched bool
+}
+
+// Match starts a new pattern match on err
+// Cases are evaluated in order and only the first matching case runs
+// A nil error never matches any case, including Default
+//
+// Example:
+//
+//	fail.Match(err).
+//		Case(AuthInvalidCredentials, func(e *fail.Error) {
+//			log.Info("invalid credentials attempt")
+//		}).
+//		CaseSystem(func(e *fail.Error) {
+//			alert(e)
+//		}).
+//		Default(func(err error) {
+//			log.Error("unexpected error", err)
+//		})
+func Match(err error) *ErrorMatcher {
+	return &ErrorMatcher{err: err, fe: AsFail(err)}
+}
+
+// Case runs fn if the error has the given ID
+func (m *ErrorMatcher) Case(id ErrorID, fn func(*Error)) *ErrorMatcher {
+	return m.when("id", func(e *Error) bool { return matchID(e, id) }, fn)
+}
+
+// CaseAny runs fn if the error has any of the given IDs
+func (m *ErrorMatcher) CaseAny(fn func(*Error), ids ...ErrorID) *ErrorMatcher {
+	return m.when("any", func(e *Error) bool { return matchAnyID(e, ids) }, fn)
+}
+
+// CaseSystem runs fn if the error is a system error
+func (m *ErrorMatcher) CaseSystem(fn func(*Error)) *ErrorMatcher {
+	return m.when("system", func(e *Error) bool { return e.IsSystem }, fn)
+}
+
+// CaseDomain runs fn if the error is a domain (expected) error
+func (m *ErrorMatcher) CaseDomain(fn func(*Error)) *ErrorMatcher {
+	return m.when("domain", func(e *Error) bool { return !e.IsSystem }, fn)
+}
+
+// CaseInDomain runs fn if the error ID belongs to the given ID domain (e.g., "AUTH")
+func (m *ErrorMatcher) CaseInDomain(domain string, fn func(*Error)) *ErrorMatcher {
+	return m.when("in_domain", func(e *Error) bool { return e.ID.Domain() == domain }, fn)
+}
+
+// CaseLevel runs fn if the error ID has exactly the given severity level
+func (m *ErrorMatcher) CaseLevel(level int, fn func(*Error)) *ErrorMatcher {
+	return m.when("level", func(e *Error) bool { return e.ID.Level() == level }, fn)
+}
+
+// CaseMinLevel runs fn if the error ID severity level is greater than or equal to level
+func (m *ErrorMatcher) CaseMinLevel(level int, fn func(*Error)) *ErrorMatcher {
+	return m.when("min_level", func(e *Error) bool { return e.ID.Level() >= level }, fn)
+}
+
+// CaseWhen runs fn if the predicate returns true for the error
+func (m *ErrorMatcher) CaseWhen(pred func(*Error) bool, fn func(*Error)) *ErrorMatcher {
+	return m.when("when", pred, fn)
+}
+
+// Default runs fn with the original error if no previous case matched
+func (m *ErrorMatcher) Default(fn func(error)) {
+	if m.matched || m.err == nil {
+		return
+	}
+	m.matched = true
+	fn(m.err)
+}
+
+// Matched returns true if any case has matched so far
+func (m *ErrorMatcher) Matched() bool {
+	return m.matched
+}
+
+func (m *ErrorMatcher) when(kind string, pred func(*Error) bool, fn func(*Error)) *ErrorMatcher {
+	if m.matched || m.fe == nil || !pred(m.fe) {
+		return m
+	}
+	m.matched = true
+	runMatchHook(m.fe, kind)
+	fn(m.fe)
+	return m
+}
+
+// ValueMatcher is the value-returning version of ErrorMatcher
+// Since Go methods can't have type parameters, T is fixed by MatchValue
+type ValueMatcher[T any] struct {
+	err     error
+	fe      *Error
+	matched bool
+	value   T
+}
+
+// MatchValue starts a new pattern match on err where each case produces a value
+//
+// Example:
+//
+//	status := fail.MatchValue[int](err).
+//		Case(UserNotFound, func(e *fail.Error) int { return 404 }).
+//		CaseDomain(func(e *fail.Error) int { return 400 }).
+//		Default(func(err error) int { return 500 })
+func MatchValue[T any](err error) *ValueMatcher[T] {
+	return &ValueMatcher[T]{err: err, fe: AsFail(err)}
+}
+
+// Case produces a value with fn if the error has the given ID
+func (m *ValueMatcher[T]) Case(id ErrorID, fn func(*Error) T) *ValueMatcher[T] {
+	return m.when("id", func(e *Error) bool { return matchID(e, id) }, fn)
+}
+
+// CaseAny produces a value with fn if the error has any of the given IDs
+func (m *ValueMatcher[T]) CaseAny(fn func(*Error) T, ids ...ErrorID) *ValueMatcher[T] {
+	return m.when("any", func(e *Error) bool { return matchAnyID(e, ids) }, fn)
+}
+
+// CaseSystem produces a value with fn if the error is a system error
+func (m *ValueMatcher[T]) CaseSystem(fn func(*Error) T) *ValueMatcher[T] {
+	return m.when("system", func(e *Error) bool { return e.IsSystem }, fn)
+}
+
+// CaseDomain produces a value with fn if the error is a domain (expected) error
+func (m *ValueMatcher[T]) CaseDomain(fn func(*Error) T) *ValueMatcher[T] {
+	return m.when("domain", func(e *Error) bool { return !e.IsSystem }, fn)
+}
+
+// CaseInDomain produces a value with fn if the error ID belongs to the given ID domain
+func (m *ValueMatcher[T]) CaseInDomain(domain string, fn func(*Error) T) *ValueMatcher[T] {
+	return m.when("in_domain", func(e *Error) bool { return e.ID.Domain() == domain }, fn)
+}
+
+// CaseLevel produces a value with fn if the error ID has exactly the given severity level
+func (m *ValueMatcher[T]) CaseLevel(level int, fn func(*Error) T) *ValueMatcher[T] {
+	return m.when("level", func(e *Error) bool { return e.ID.Level() == level }, fn)
+}
+
+// CaseMinLevel produces a value with fn if the error ID severity level is greater than or equal to level
+func (m *ValueMatcher[T]) CaseMinLevel(level int, fn func(*Error) T) *ValueMatcher[T] {
+	return m.when("min_level", func(e *Error) bool { return e.ID.Level() >= level }, fn)
+}
+
+// CaseWhen produces a value with fn if the predicate returns true for the error
+func (m *ValueMatcher[T]) CaseWhen(pred func(*Error) bool, fn func(*Error) T) *ValueMatcher[T] {
+	return m.when("when", pred, fn)
+}
+
+// Default produces a value with fn if no previous case matched and returns the final value
+// For a nil error fn is not called and the zero value is returned
+func (m *ValueMatcher[T]) Default(fn func(error) T) T {
+	if !m.matched && m.err != nil {
+		m.matched = true
+		m.value = fn(m.err)
+	}
+	return m.value
+}
+
+// Value returns the produced value and whether any case matched
+func (m *ValueMatcher[T]) Value() (T, bool) {
+	return m.value, m.matched
+}
+
+func (m *ValueMatcher[T]) when(kind string, pred func(*Error) bool, fn func(*Error) T) *ValueMatcher[T] {
+	if m.matched || m.fe == nil || !pred(m.fe) {
+		return m
+	}
+	m.matched = true
+	runMatchHook(m.fe, kind)
+	m.value = fn(m.fe)
+	return m
+}
+
+func matchID(e *Error, id ErrorID) bool {
+	return sameID(e.ID, id)
+}
+
+func matchAnyID(e *Error, ids []ErrorID) bool {
+	for _, id := range ids {
+		if matchID(e, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func runMatchHook(e *Error, kind string) {
+	// Get registry first (with fallback)
+	reg := e.registry
+	if reg == nil {
+		reg = global
+	}
+
+	reg.hooks.runMatch(e, map[string]any{
+		"id":   e.ID.String(),
+		"case": kind,
+	})
+}
@@ -0,0 +1,121 @@
+package fail
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// MetaKey is a typed key for Error.Meta
+// Values are still stored in the Meta map under Name(), so typed and string based
+// access (AddMeta, GetMeta) can be mixed
+type MetaKey[T any] struct {
+	name string
+}
+
+var (
+	metaKeyTypes   = map[string]reflect.Type{}
+	metaKeyTypesMu sync.Mutex
+)
+
+// NewMetaKey declares a typed meta key, prefer namespaced names for your own keys
+// (e.g., "billing.invoice_id") to stay clear of other packages
+//
+// Declaring the same name twice with the same type returns an equivalent key,
+// declaring it with a different type panics since values would fail to assert
+//
+// Example:
+//
+//	var RequestID = fail.NewMetaKey[string]("request_id")
+//
+//	err := fail.SetMeta(fail.New(UserNotFound), RequestID, "abc")
+//	id, ok := fail.GetMetaAs(err, RequestID)
+func NewMetaKey[T any](name string) MetaKey[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	metaKeyTypesMu.Lock()
+	defer metaKeyTypesMu.Unlock()
+
+	if existing, ok := metaKeyTypes[name]; ok && existing != typ {
+		panic(fmt.Sprintf("meta key '%s' already declared with type %s, cannot redeclare it as %s",
+			name, existing, typ))
+	}
+	metaKeyTypes[name] = typ
+
+	return MetaKey[T]{name: name}
+}
+
+// Name returns the key used in the Meta map
+func (k MetaKey[T]) Name() string {
+	return k.name
+}
+
+// String returns the key name
+func (k MetaKey[T]) String() string {
+	return k.name
+}
+
+// SetMeta sets a typed metadata value, it behaves like AddMeta on static errors
+func SetMeta[T any](err *Error, key MetaKey[T], value T) *Error {
+	err, abort := err.prepare("SetMeta")
+	if abort {
+		return err
+	}
+	setMeta(err, key, value)
+	return err
+}
+
+// GetMetaAs extracts a typed metadata value from an error
+// Returns false if the key is missing or holds a value of another type
+func GetMetaAs[T any](err error, key MetaKey[T]) (T, bool) {
+	if e, ok := As(err); ok {
+		return getMeta(e, key)
+	}
+	var zero T
+	return zero, false
+}
+
+// Library meta keys, stored under the same names as before typed keys existed so
+// AddMeta/GetMeta callers and serialized errors see no difference
+var (
+	MetaRetryable             = libraryMetaKey[bool]("retryable")
+	MetaRetryAfter            = libraryMetaKey[time.Duration]("retry_after")
+	MetaRetryHistory          = libraryMetaKey[RetryHistory]("retry_history")
+	MetaAttempts              = libraryMetaKey[int]("attempts")
+	MetaReason                = libraryMetaKey[string]("reason")
+	MetaRetrySuppressed       = libraryMetaKey[bool]("retry_suppressed")
+	MetaRetrySuppressedReason = libraryMetaKey[string]("retry_suppressed_reason")
+	MetaRetryBudgetTokens     = libraryMetaKey[float64]("retry_budget_tokens")
+	MetaValidations           = libraryMetaKey[[]ValidationError]("validations")
+	MetaTraces                = libraryMetaKey[[]string]("traces")
+	MetaDebug                 = libraryMetaKey[[]string]("debug")
+	MetaChainStep             = libraryMetaKey[string]("chain_step")
+	MetaChainStepIndex        = libraryMetaKey[int]("chain_step_index")
+	MetaCircuit               = libraryMetaKey[string]("circuit")
+	MetaCircuitState          = libraryMetaKey[string]("circuit_state")
+)
+
+// libraryMetaTypes maps the name of each library key to its type, see decodeMetaValue
+var libraryMetaTypes = map[string]reflect.Type{}
+
+// libraryMetaKey returns a typed view over an existing library meta name
+// Library keys are not declared through NewMetaKey, a user key with the same name and
+// another type doesn't panic, reads through the library key just miss its values
+func libraryMetaKey[T any](name string) MetaKey[T] {
+	libraryMetaTypes[name] = reflect.TypeOf((*T)(nil)).Elem()
+	return MetaKey[T]{name: name}
+}
+
+// setMeta stores a typed value without the static check, for builders that already did it
+func setMeta[T any](e *Error, key MetaKey[T], value T) {
+	if e.Meta == nil {
+		e.Meta = make(map[string]any)
+	}
+	e.Meta[key.name] = value
+}
+
+func getMeta[T any](e *Error, key MetaKey[T]) (T, bool) {
+	v, ok := e.Meta[key.name].(T)
+	return v, ok
+}
@@ -1,6 +1,7 @@
 package localization
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/MintzyG/fail/v3"
@@ -76,3 +77,19 @@ func (r *Localizer) RegisterLocalizations(locale string, translations map[fail.E
 		}
 	}
 }
+
+// Locales returns the locales holding at least one translation, sorted
+// It implements fail.LocaleLister, used by fail.ExportCatalog
+func (r *Localizer) Locales() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	locales := make([]string, 0, len(r.data))
+	for locale, translations := range r.data {
+		if len(translations) > 0 {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
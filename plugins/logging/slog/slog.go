@@ -0,0 +1,199 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+// Logger implements fail.Logger using log/slog
+type Logger struct {
+	logger *slog.Logger
+	config Config
+}
+
+// Config configures the slog logger
+type Config struct {
+	// Logger is the destination (default: slog.Default())
+	Logger *slog.Logger
+
+	// Message is the log record message (default: the rendered error message)
+	Message func(*fail.Error) string
+
+	// Level maps an error to a log level (default: DefaultLevel)
+	Level func(*fail.Error) slog.Level
+
+	// MetaFilter decides which meta keys are logged, nil logs every key
+	MetaFilter func(key string) bool
+
+	// IncludeInternal adds the internal message as "internal" to the error group
+	IncludeInternal bool
+
+	// ContextAttrs extracts attributes from the context (request IDs, tenants, etc.)
+	ContextAttrs func(ctx context.Context) []slog.Attr
+
+	// GroupKey is the key of the error group (default: "error")
+	GroupKey string
+}
+
+// DefaultLevel maps ErrorID.Level() to slog levels
+// 0 is Info, 1 is Warn, 2 and above are Error
+func DefaultLevel(err *fail.Error) slog.Level {
+	switch level := err.ID.Level(); {
+	case level <= 0:
+		return slog.LevelInfo
+	case level == 1:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// New creates a new slog logger
+//
+// Example:
+//
+//	fail.SetLogger(slog.New(
+//		slog.WithLogger(logger),
+//		slog.WithMetaKeys("request_id", "user_id"),
+//		slog.WithInternalMessage(),
+//	))
+func New(opts ...Option) *Logger {
+	config := Config{
+		Logger:   slog.Default(),
+		Level:    DefaultLevel,
+		GroupKey: "error",
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &Logger{
+		logger: config.Logger,
+		config: config,
+	}
+}
+
+// Option configures the logger
+type Option func(*Config)
+
+// WithLogger sets the destination logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithMessage customizes the log record message
+func WithMessage(fn func(*fail.Error) string) Option {
+	return func(c *Config) {
+		c.Message = fn
+	}
+}
+
+// WithLevel sets a custom level mapping
+func WithLevel(fn func(*fail.Error) slog.Level) Option {
+	return func(c *Config) {
+		c.Level = fn
+	}
+}
+
+// WithLevels maps ErrorID.Level() values to slog levels, unmapped levels use DefaultLevel
+func WithLevels(levels map[int]slog.Level) Option {
+	return func(c *Config) {
+		c.Level = func(err *fail.Error) slog.Level {
+			if level, ok := levels[err.ID.Level()]; ok {
+				return level
+			}
+			return DefaultLevel(err)
+		}
+	}
+}
+
+// WithMetaFilter sets a custom meta key filter
+func WithMetaFilter(fn func(key string) bool) Option {
+	return func(c *Config) {
+		c.MetaFilter = fn
+	}
+}
+
+// WithMetaKeys logs only the given meta keys
+func WithMetaKeys(keys ...string) Option {
+	allowed := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		allowed[k] = struct{}{}
+	}
+	return WithMetaFilter(func(key string) bool {
+		_, ok := allowed[key]
+		return ok
+	})
+}
+
+// WithoutMetaKeys logs every meta key except the given ones
+func WithoutMetaKeys(keys ...string) Option {
+	denied := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		denied[k] = struct{}{}
+	}
+	return WithMetaFilter(func(key string) bool {
+		_, ok := denied[key]
+		return !ok
+	})
+}
+
+// WithInternalMessage includes the internal message, keep it off for logs shipped to third parties
+func WithInternalMessage() Option {
+	return func(c *Config) {
+		c.IncludeInternal = true
+	}
+}
+
+// WithContextAttrs extracts attributes from the context passed to LogCtx
+func WithContextAttrs(fn func(ctx context.Context) []slog.Attr) Option {
+	return func(c *Config) {
+		c.ContextAttrs = fn
+	}
+}
+
+// WithGroupKey sets the key of the error group
+func WithGroupKey(key string) Option {
+	return func(c *Config) {
+		c.GroupKey = key
+	}
+}
+
+// Log logs an error
+func (l *Logger) Log(err *fail.Error) {
+	l.LogCtx(context.Background(), err)
+}
+
+// LogCtx logs an error with context, context attributes are added at the top level
+func (l *Logger) LogCtx(ctx context.Context, err *fail.Error) {
+	if err == nil {
+		return
+	}
+
+	level := l.config.Level(err)
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	message := err.GetRendered()
+	if l.config.Message != nil {
+		message = l.config.Message(err)
+	}
+
+	group := err.LogAttrs(l.config.MetaFilter)
+	if l.config.IncludeInternal && err.InternalMessage != "" {
+		group = append(group, slog.String("internal", err.InternalMessage))
+	}
+
+	attrs := []slog.Attr{{Key: l.config.GroupKey, Value: slog.GroupValue(group...)}}
+	if l.config.ContextAttrs != nil {
+		attrs = append(attrs, l.config.ContextAttrs(ctx)...)
+	}
+
+	l.logger.LogAttrs(ctx, level, message, attrs...)
+}
@@ -19,6 +19,12 @@ type Registry struct {
 
 	pendingLocalizations map[ErrorID]map[string]string
 
+	retryPolicies map[ErrorID]RetryPolicy
+
+	captureStackIDs     map[ErrorID]bool
+	captureStackLevel   int
+	captureStackByLevel bool
+
 	hooks Hooks
 
 	tracer Tracer
@@ -27,6 +33,7 @@ type Registry struct {
 	allowInternalLogs      bool
 	allowStaticMutations   bool
 	panicOnStaticMutations bool
+	copyOnWrite            bool
 }
 
 var allowRuntimePanics bool
@@ -115,7 +122,10 @@ func (r *Registry) Register(err *Error) *Error {
 		return nil
 	}
 
-	r.errors[err.ID.String()] = err
+	// Keep our own template, the caller's error is left untouched
+	tmpl := err.Clone()
+	tmpl.shared = true
+	r.errors[err.ID.String()] = tmpl
 	return nil
 }
 
@@ -136,6 +146,10 @@ func (r *Registry) RegisterMany(defs ...*ErrorDefinition) *Error {
 			isStatic: def.ID.IsStatic(),
 		}); err != nil {
 			failures[err.ID.String()] = err
+			continue
+		}
+		if def.RetryPolicy != nil {
+			_ = r.SetRetryPolicy(def.ID, *def.RetryPolicy)
 		}
 	}
 
@@ -151,6 +165,12 @@ func (r *Registry) RegisterMany(defs ...*ErrorDefinition) *Error {
 }
 
 func (r *Registry) New(id ErrorID) *Error {
+	return r.newError(id, true)
+}
+
+// newError creates an error from the template of id, notifyDeprecated is false for
+// Form sentinels so declaring them doesn't count as using a deprecated ID
+func (r *Registry) newError(id ErrorID, notifyDeprecated bool) *Error {
 	// Verify the ErrorID is trusted
 	if !id.IsRegistered() {
 		if r.allowInternalLogs {
@@ -161,6 +181,7 @@ func (r *Registry) New(id ErrorID) *Error {
 
 	r.mu.RLock()
 	def, exists := r.errors[id.String()]
+	captureStack := r.shouldCaptureStack(id)
 	r.mu.RUnlock()
 
 	if !exists {
@@ -176,6 +197,10 @@ func (r *Registry) New(id ErrorID) *Error {
 		isStatic:     id.IsStatic(),
 	}
 
+	if captureStack {
+		err.stack = callers(3)
+	}
+
 	// Copy default meta if present
 	if len(def.Meta) > 0 {
 		err.Meta = make(map[string]any, len(def.Meta))
@@ -187,5 +212,8 @@ func (r *Registry) New(id ErrorID) *Error {
 	// Run onCreate hooks
 	r.hooks.runCreate(err, map[string]any{"create": def.ID.String()})
 
+	if notifyDeprecated {
+		r.notifyDeprecated(err)
+	}
 	return err
 }
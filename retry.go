@@ -1,8 +1,10 @@
 package fail
 
 import (
+	"context"
 	"errors"
 	"math/rand"
+	"slices"
 	"sync/atomic"
 	"time"
 )
@@ -15,6 +17,37 @@ type RetryConfig struct {
 	// Delay returns how long to wait BEFORE the next attempt.
 	// attempt starts at 1 for the first retry (not the first call).
 	Delay func(attempt int) time.Duration
+
+	// MaxDelay caps any single delay returned by Delay (0 = no cap)
+	MaxDelay time.Duration
+
+	// MaxRetryAfter caps retry_after hints carried by errors (0 = no cap)
+	// Hints set with (*Error).RetryAfter are preferred over Delay
+	MaxRetryAfter time.Duration
+
+	// MaxElapsed stops retrying once the next delay would exceed this total time budget (0 = no limit)
+	MaxElapsed time.Duration
+
+	// OnAttempt hooks run after every attempt with its 1-based number and its error (nil on success)
+	OnAttempt []func(attempt int, err error)
+
+	// Budget limits retries to a ratio of successful calls, shared across callers (nil = unlimited)
+	// When exhausted the original error chain is returned wrapped, As and GetMeta on it
+	// see MetaRetrySuppressed metadata
+	Budget *RetryBudget
+
+	// ExhaustedError makes retries that run out of attempts (or MaxElapsed) return a
+	// RetryExhausted error carrying the full attempt history instead of only the last error
+	// The history is the cause of the returned *Error (and part of it for RetryAborted),
+	// so errors.Is and errors.As reach every attempt error, not only the last one
+	ExhaustedError bool
+
+	// maxAttemptsDefault is true when MaxAttempts was not chosen by the caller (global
+	// config, zero value, NewRetryConfig without WithMaxAttempts), a RetryPolicy may replace it
+	maxAttemptsDefault bool
+
+	// delayDefault is the same for Delay (global config, nil, NewRetryConfig without WithBackoff)
+	delayDefault bool
 }
 
 var retryConfig atomic.Pointer[RetryConfig]
@@ -44,6 +77,17 @@ func getRetryConfig() *RetryConfig {
 	return retryConfig.Load()
 }
 
+// globalRetryConfig returns a copy of the global config, its MaxAttempts and Delay are
+// process wide defaults that a RetryPolicy replaces
+func globalRetryConfig() RetryConfig {
+	config := *getRetryConfig()
+	config.maxAttemptsDefault = true
+	config.delayDefault = true
+	return config
+}
+
+// IsRetryableDefault reports whether err is a retryable *Error
+// An explicit MetaRetryable value wins, otherwise the RetryPolicy registered for the ID decides
 func IsRetryableDefault(err error) bool {
 	if err == nil {
 		return false
@@ -54,134 +98,251 @@ func IsRetryableDefault(err error) bool {
 	if !errors.As(err, &fe) {
 		return false
 	} else {
-		if v, ok := fe.Meta["retryable"].(bool); ok {
+		if v, ok := getMeta(fe, MetaRetryable); ok {
 			return v
 		}
+		// Fall back to the policy registered for the ID
+		if policy, ok := fe.RetryPolicy(); ok {
+			return policy.Retryable
+		}
 		return false
 	}
 }
 
 // Retry executes a function with retries
 func Retry(fn func() error) error {
-	var lastErr error
+	return RetryCtx(context.Background(), func(context.Context) error { return fn() })
+}
 
-	cfg := getRetryConfig()
+// RetryCFG executes a function with retries using the passed config
+func RetryCFG(config RetryConfig, fn func() error) error {
+	return RetryCtxCFG(context.Background(), config, func(context.Context) error { return fn() })
+}
 
-	for i := 0; i < cfg.MaxAttempts; i++ {
-		err := fn()
-		if err == nil {
-			return nil
-		}
+// RetryValue retries a function that returns (T, error)
+func RetryValue[T any](fn func() (T, error)) (T, error) {
+	return RetryValueCtx(context.Background(), func(context.Context) (T, error) { return fn() })
+}
 
-		lastErr = err
+// RetryValueCFG retries a function that returns (T, error) using the passed config
+func RetryValueCFG[T any](config RetryConfig, fn func() (T, error)) (T, error) {
+	return RetryValueCtxCFG(context.Background(), config, func(context.Context) (T, error) { return fn() })
+}
 
-		if e, ok := As(err); ok {
-			if !cfg.ShouldRetry(e) {
-				return err
-			}
-		} else {
-			return err
-		}
+// RetryCtx executes a function with retries, honoring ctx cancellation and deadline
+// ctx is passed to every attempt. If ctx is done before an attempt, during a delay, or a
+// delay would overshoot the ctx deadline, a RetryAborted error is returned that wraps both
+// ctx.Err() and the last attempt error
+func RetryCtx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runRetry(ctx, globalRetryConfig(), fn)
+}
 
-		if cfg.Delay != nil && i < cfg.MaxAttempts-1 {
-			time.Sleep(cfg.Delay(i + 1))
-		}
-	}
+// RetryCtxCFG is RetryCtx using the passed config
+func RetryCtxCFG(ctx context.Context, config RetryConfig, fn func(ctx context.Context) error) error {
+	normalizeConfig(&config)
+	return runRetry(ctx, config, fn)
+}
 
-	return lastErr
+// RetryValueCtx is the (T, error) version of RetryCtx
+func RetryValueCtx[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) (T, error) {
+	return RetryValueCtxCFG(ctx, globalRetryConfig(), fn)
 }
 
-// RetryCFG executes a function with retries using the passed config
-func RetryCFG(config RetryConfig, fn func() error) error {
+// RetryValueCtxCFG is the (T, error) version of RetryCtxCFG
+func RetryValueCtxCFG[T any](ctx context.Context, config RetryConfig, fn func(ctx context.Context) (T, error)) (T, error) {
 	normalizeConfig(&config)
 
+	var zero T
+	var result T
+
+	err := runRetry(ctx, config, func(ctx context.Context) error {
+		v, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		result = v
+		return nil
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	return result, nil
+}
+
+// runRetry is the single retry loop shared by every Retry* function
+// Only *Error values accepted by config.ShouldRetry are retried
+// A RetryPolicy registered for the error's ID only provides defaults: its MaxAttempts and
+// Delay apply when the caller didn't choose them, see maxAttemptsDefault and delayDefault
+func runRetry(ctx context.Context, config RetryConfig, fn func(ctx context.Context) error) error {
 	var lastErr error
+	var lastRetried *Error
+	var lastDelay time.Duration
+	var history RetryHistory
+	var failedDomains []string
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return retryAborted(ctxErr, lastErr, attempt-1, history)
+		}
 
-	for i := 0; i < config.MaxAttempts; i++ {
-		err := fn()
+		err := fn(ctx)
+		config.runOnAttempt(attempt, err)
 		if err == nil {
+			if config.Budget != nil {
+				config.Budget.recordSuccess(failedDomains)
+			}
+			if lastRetried != nil {
+				retryHooks(lastRetried).runRetrySuccess(lastRetried, attempt, lastDelay)
+			}
 			return nil
 		}
 
 		lastErr = err
+		if config.ExhaustedError {
+			history = append(history, RetryAttempt{Number: attempt, Err: err, At: time.Now()})
+		}
 
-		if e, ok := As(err); ok {
-			if !config.ShouldRetry(e) {
-				return err
-			}
-		} else {
+		// Non-fail errors are not retryable
+		e, ok := As(err)
+		if !ok || !config.ShouldRetry(e) {
 			return err
 		}
 
-		if config.Delay != nil && i < config.MaxAttempts-1 {
-			time.Sleep(config.Delay(i + 1))
+		if config.Budget != nil {
+			config.Budget.recordFailure(e)
+			if domain := e.ID.Domain(); !slices.Contains(failedDomains, domain) {
+				failedDomains = append(failedDomains, domain)
+			}
 		}
-	}
 
-	return lastErr
-}
+		policy, _ := e.RetryPolicy()
+		maxAttempts := config.MaxAttempts
+		if policy.MaxAttempts > 0 && config.maxAttemptsDefault {
+			maxAttempts = policy.MaxAttempts
+		}
 
-// RetryValue retries a function that returns (T, error)
-func RetryValue[T any](fn func() (T, error)) (T, error) {
-	var zero T
-	var lastErr error
+		if attempt >= maxAttempts {
+			retryHooks(e).runRetryExhausted(e, attempt, 0)
+			break
+		}
 
-	cfg := getRetryConfig()
+		if config.Budget != nil {
+			if ok, tokens := config.Budget.allowRetry(e); !ok {
+				retryHooks(e).runRetryExhausted(e, attempt, 0)
+				return retrySuppressed(err, e, tokens)
+			}
+		}
 
-	for i := 0; i < cfg.MaxAttempts; i++ {
-		v, err := fn()
-		if err == nil {
-			return v, nil
+		delay := config.delayFor(attempt, e, policy)
+		if config.MaxElapsed > 0 && time.Since(start)+delay > config.MaxElapsed {
+			retryHooks(e).runRetryExhausted(e, attempt, delay)
+			break
 		}
 
-		lastErr = err
+		// Don't sleep if we would wake up after the deadline anyway
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return retryAborted(context.DeadlineExceeded, lastErr, attempt, history)
+		}
 
-		// Only retry fail.Error that is retryable
-		if e, ok := As(err); ok {
-			if !cfg.ShouldRetry(e) {
-				return zero, err
-			}
-		} else {
-			// Non-fail errors are not retryable
-			return zero, err
+		if len(history) > 0 {
+			history[len(history)-1].Delay = delay
 		}
 
-		if cfg.Delay != nil && i < cfg.MaxAttempts-1 {
-			time.Sleep(cfg.Delay(i + 1))
+		retryHooks(e).runRetry(e, attempt, delay)
+		lastRetried, lastDelay = e, delay
+
+		if err := sleepCtx(ctx, delay); err != nil {
+			return retryAborted(err, lastErr, attempt, history)
 		}
 	}
 
-	return zero, lastErr
+	if config.ExhaustedError {
+		return retryExhausted(history)
+	}
+
+	return lastErr
 }
 
-func RetryValueCFG[T any](config RetryConfig, fn func() (T, error)) (T, error) {
-	normalizeConfig(&config)
+// retryHooks returns the hooks of the registry err belongs to
+func retryHooks(err *Error) *Hooks {
+	reg := err.registry
+	if reg == nil {
+		reg = global
+	}
+	return &reg.hooks
+}
 
-	var zero T
-	var lastErr error
+// sleepCtx waits for d or until ctx is done, returning ctx.Err() in the latter case
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
 
-	for i := 0; i < config.MaxAttempts; i++ {
-		v, err := fn()
-		if err == nil {
-			return v, nil
-		}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
 
-		lastErr = err
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
-		if e, ok := As(err); ok {
-			if !config.ShouldRetry(e) {
-				return zero, err
-			}
-		} else {
-			return zero, err
-		}
+// retryAborted builds the RetryAborted error, its cause joins ctxErr and lastErr (or
+// the whole history when recorded) so errors.Is works for the context error and the
+// attempt errors
+func retryAborted(ctxErr, lastErr error, attempts int, history RetryHistory) *Error {
+	err := New(RetryAborted).WithArgs(attempts)
+	setMeta(err, MetaReason, ctxErr.Error())
+	setMeta(err, MetaAttempts, attempts)
+
+	// The history ends with lastErr, wrapping it keeps every attempt reachable
+	cause := lastErr
+	if len(history) > 0 {
+		setMeta(err, MetaRetryHistory, history)
+		cause = history
+	}
+
+	return err.With(errors.Join(ctxErr, cause)).Render()
+}
 
-		if config.Delay != nil && i < config.MaxAttempts-1 {
-			time.Sleep(config.Delay(i + 1))
+// delayFor returns the wait before the next attempt
+// A retry_after hint on err wins and is capped by MaxRetryAfter, otherwise
+// the config Delay (or the policy Delay when the config one is a default) is used and
+// capped by MaxDelay
+func (c *RetryConfig) delayFor(attempt int, err *Error, policy RetryPolicy) time.Duration {
+	if hint, ok := GetRetryAfter(err); ok {
+		if c.MaxRetryAfter > 0 && hint > c.MaxRetryAfter {
+			hint = c.MaxRetryAfter
 		}
+		return hint
+	}
+
+	delay := c.Delay
+	if policy.Delay != nil && c.delayDefault {
+		delay = policy.Delay
+	}
+
+	if delay == nil {
+		return 0
+	}
+	d := delay(attempt)
+	if c.MaxDelay > 0 && d > c.MaxDelay {
+		d = c.MaxDelay
 	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
 
-	return zero, lastErr
+func (c *RetryConfig) runOnAttempt(attempt int, err error) {
+	executeHooks(c.OnAttempt, func(fn func(int, error)) {
+		fn(attempt, err)
+	})
 }
 
 func BackoffConstant(d time.Duration) func(int) time.Duration {
@@ -215,6 +376,10 @@ func WithJitter(delay func(int) time.Duration, jitterFraction float64) func(int)
 func normalizeConfig(c *RetryConfig) {
 	if c.MaxAttempts <= 0 {
 		c.MaxAttempts = 1
+		c.maxAttemptsDefault = true
+	}
+	if c.Delay == nil {
+		c.delayDefault = true
 	}
 	if c.ShouldRetry == nil {
 		c.ShouldRetry = IsRetryableDefault
@@ -241,6 +406,17 @@ Retry with a returned value:
 	})
 */
 
+/*
+Retry honoring a request context (cancellation and deadline):
+
+	err := fail.RetryCtx(r.Context(), func(ctx context.Context) error {
+		return repo.Save(ctx, user)
+	})
+	if fail.Is(err, fail.RetryAborted) {
+		// errors.Is(err, context.Canceled) and the last attempt error both match
+	}
+*/
+
 /*
 Override retry behavior per call:
 
@@ -257,18 +433,21 @@ Override retry behavior per call:
 /*
 Retry with constant backoff:
 
-	cfg := fail.NewRetryConfig(
+	cfg, err := fail.NewRetryConfig(
 		fail.WithMaxAttempts(4),
 		fail.WithBackoff(fail.BackoffConstant(500 * time.Millisecond)),
 	)
+	if err != nil {
+		return err // FailRetryConfigInvalid
+	}
 
-	err := fail.RetryCFG(cfg, doWork)
+	err = fail.RetryCFG(cfg, doWork)
 */
 
 /*
 Retry with linear backoff:
 
-	cfg := fail.NewRetryConfig(
+	cfg := fail.MustNewRetryConfig(
 		fail.WithBackoff(fail.BackoffLinear(200 * time.Millisecond)),
 	)
 
@@ -278,7 +457,7 @@ Retry with linear backoff:
 /*
 Retry with exponential backoff:
 
-	cfg := fail.NewRetryConfig(
+	cfg := fail.MustNewRetryConfig(
 		fail.WithBackoff(fail.BackoffExponential(100 * time.Millisecond)),
 	)
 
@@ -288,7 +467,7 @@ Retry with exponential backoff:
 /*
 Retry with exponential backoff + jitter (recommended for distributed systems):
 
-	cfg := fail.NewRetryConfig(
+	cfg := fail.MustNewRetryConfig(
 		fail.WithMaxAttempts(5),
 		fail.WithBackoff(
 			fail.WithJitter(
@@ -304,7 +483,7 @@ Retry with exponential backoff + jitter (recommended for distributed systems):
 /*
 Retry a function that returns a value with backoff:
 
-	cfg := fail.NewRetryConfig(
+	cfg := fail.MustNewRetryConfig(
 		fail.WithBackoff(fail.BackoffLinear(300 * time.Millisecond)),
 	)
 
@@ -312,3 +491,19 @@ Retry a function that returns a value with backoff:
 		return fetchSomething()
 	})
 */
+
+/*
+Retry with caps on single delays, total time and per-attempt hooks:
+
+	cfg := fail.MustNewRetryConfig(
+		fail.WithMaxAttempts(10),
+		fail.WithBackoff(fail.BackoffExponential(100*time.Millisecond)),
+		fail.WithMaxDelay(2*time.Second),
+		fail.WithMaxElapsed(10*time.Second),
+		fail.WithOnAttempt(func(attempt int, err error) {
+			log.Printf("attempt %d: %v", attempt, err)
+		}),
+	)
+
+	err := fail.RetryCFG(cfg, doWork)
+*/
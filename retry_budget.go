@@ -0,0 +1,160 @@
+package fail
+
+import (
+	"sync"
+)
+
+// RetryBudgetConfig configures a RetryBudget
+// Zero values are replaced by the documented defaults
+type RetryBudgetConfig struct {
+	// MaxTokens is the bucket capacity, buckets start full (default: 10)
+	MaxTokens float64
+
+	// TokenRatio is the amount of tokens a successful call gives back (default: 0.1)
+	TokenRatio float64
+
+	// PerDomain keeps a separate bucket for each error ID domain instead of a single global one
+	PerDomain bool
+}
+
+// RetryBudget limits retries to a ratio of successful calls, shared across callers
+// It follows gRPC retry throttling: every retryable failure takes 1 token, every
+// success gives back TokenRatio tokens, and retries are only allowed while the
+// bucket holds more than half of MaxTokens
+//
+// In PerDomain mode failures are charged to the bucket of their ID domain and
+// successes are credited once to each domain that failed earlier in the same call
+// A success on the first attempt has no known domain and credits nothing, so
+// traffic to healthy domains never refills the bucket of a failing one
+type RetryBudget struct {
+	mu      sync.Mutex
+	config  RetryBudgetConfig
+	buckets map[string]float64 // domain ("" when global) -> tokens
+}
+
+// NewRetryBudget creates a retry budget to be shared through RetryConfig.Budget
+//
+// Example:
+//
+//	var dbBudget = fail.NewRetryBudget(fail.RetryBudgetConfig{MaxTokens: 100, TokenRatio: 0.1})
+//
+//	cfg := fail.MustNewRetryConfig(fail.WithBudget(dbBudget))
+func NewRetryBudget(config RetryBudgetConfig) *RetryBudget {
+	if config.MaxTokens <= 0 {
+		config.MaxTokens = 10
+	}
+	if config.TokenRatio <= 0 {
+		config.TokenRatio = 0.1
+	}
+	return &RetryBudget{
+		config:  config,
+		buckets: make(map[string]float64),
+	}
+}
+
+// Tokens returns the tokens left for a domain, domain is ignored if the budget is global
+func (b *RetryBudget) Tokens(domain string) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens(b.key(domain))
+}
+
+// Reset refills every bucket
+func (b *RetryBudget) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buckets = make(map[string]float64)
+}
+
+// recordFailure charges one token to the bucket of err
+func (b *RetryBudget) recordFailure(err *Error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := b.key(err.ID.Domain())
+	tokens := b.tokens(key) - 1
+	if tokens < 0 {
+		tokens = 0
+	}
+	b.buckets[key] = tokens
+}
+
+// recordSuccess credits TokenRatio to the bucket of each given domain once
+// The global bucket is always credited, per-domain buckets only for known domains
+func (b *RetryBudget) recordSuccess(domains []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.config.PerDomain {
+		b.credit("")
+		return
+	}
+
+	for _, d := range domains {
+		b.credit(d)
+	}
+}
+
+// allowRetry reports whether the bucket of err still allows a retry
+func (b *RetryBudget) allowRetry(err *Error) (bool, float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tokens := b.tokens(b.key(err.ID.Domain()))
+	return tokens > b.config.MaxTokens/2, tokens
+}
+
+// credit adds TokenRatio to a bucket, must hold b.mu
+func (b *RetryBudget) credit(key string) {
+	tokens := b.tokens(key) + b.config.TokenRatio
+	if tokens > b.config.MaxTokens {
+		tokens = b.config.MaxTokens
+	}
+	b.buckets[key] = tokens
+}
+
+// tokens returns the tokens of a bucket, buckets start full, must hold b.mu
+func (b *RetryBudget) tokens(key string) float64 {
+	if tokens, ok := b.buckets[key]; ok {
+		return tokens
+	}
+	return b.config.MaxTokens
+}
+
+func (b *RetryBudget) key(domain string) string {
+	if b.config.PerDomain {
+		return domain
+	}
+	return ""
+}
+
+// retrySuppressed wraps err, the caller's error chain, to explain that the budget
+// suppressed the retry of its *Error e
+// The chain is kept as is, the metadata lives on a copy of e that errors.As (and so
+// GetMeta and As) returns, shared sentinels and static errors are never mutated
+func retrySuppressed(err error, e *Error, tokens float64) error {
+	annotated := e.Clone()
+	setMeta(annotated, MetaRetrySuppressed, true)
+	setMeta(annotated, MetaRetrySuppressedReason, "retry budget exhausted")
+	setMeta(annotated, MetaRetryBudgetTokens, tokens)
+	return &suppressedError{err: err, annotated: annotated}
+}
+
+// suppressedError is the error returned when the retry budget suppressed a retry
+type suppressedError struct {
+	err       error  // the caller's error, unchanged
+	annotated *Error // copy of the *Error in err carrying the suppression metadata
+}
+
+func (s *suppressedError) Error() string { return s.err.Error() }
+
+func (s *suppressedError) Unwrap() error { return s.err }
+
+// As returns the annotated *Error so metadata lookups see the suppression
+func (s *suppressedError) As(target any) bool {
+	if t, ok := target.(**Error); ok {
+		*t = s.annotated
+		return true
+	}
+	return false
+}
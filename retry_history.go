@@ -0,0 +1,108 @@
+package fail
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RetryAttempt records the outcome of a single failed attempt
+type RetryAttempt struct {
+	Number int           `json:"number"` // 1-based attempt number
+	Err    error         `json:"-"`      // Error returned by the attempt, only its message is marshaled
+	At     time.Time     `json:"at"`     // When the attempt returned
+	Delay  time.Duration `json:"delay"`  // Wait before the next attempt (0 if none followed)
+}
+
+// retryAttemptJSON is the wire form of a RetryAttempt, the error only keeps its message
+type retryAttemptJSON struct {
+	Number int           `json:"number"`
+	Error  string        `json:"error,omitempty"`
+	At     time.Time     `json:"at"`
+	Delay  time.Duration `json:"delay"`
+}
+
+// MarshalJSON encodes the attempt with the message of its error
+func (a RetryAttempt) MarshalJSON() ([]byte, error) {
+	wire := retryAttemptJSON{Number: a.Number, At: a.At, Delay: a.Delay}
+	if a.Err != nil {
+		wire.Error = a.Err.Error()
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON decodes an attempt, its error keeps the original message
+func (a *RetryAttempt) UnmarshalJSON(data []byte) error {
+	var wire retryAttemptJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	*a = RetryAttempt{Number: wire.Number, At: wire.At, Delay: wire.Delay}
+	if wire.Error != "" {
+		a.Err = &remoteError{message: wire.Error}
+	}
+	return nil
+}
+
+// RetryHistory is the ordered list of failed attempts of a retry loop
+// It implements the Go 1.20+ multiple error unwrapping interface so errors.Is()
+// and errors.As() can match any of the underlying attempt errors
+type RetryHistory []RetryAttempt
+
+// Error implements error interface
+func (h RetryHistory) Error() string {
+	switch len(h) {
+	case 0:
+		return "no attempts"
+	case 1:
+		return fmt.Sprintf("attempt 1: %v", h[0].Err)
+	}
+
+	var b strings.Builder
+	for i, a := range h {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(&b, "attempt %d: %v", a.Number, a.Err)
+	}
+	return b.String()
+}
+
+// Unwrap returns every attempt error in order
+func (h RetryHistory) Unwrap() []error {
+	errs := make([]error, 0, len(h))
+	for _, a := range h {
+		if a.Err != nil {
+			errs = append(errs, a.Err)
+		}
+	}
+	return errs
+}
+
+// Errors returns every attempt error in order
+func (h RetryHistory) Errors() []error {
+	return h.Unwrap()
+}
+
+// Last returns the error of the last attempt or nil
+func (h RetryHistory) Last() error {
+	if len(h) == 0 {
+		return nil
+	}
+	return h[len(h)-1].Err
+}
+
+// GetRetryHistory extracts the attempt history from a RetryExhausted or RetryAborted error
+func GetRetryHistory(err error) (RetryHistory, bool) {
+	return GetMetaAs(err, MetaRetryHistory)
+}
+
+// retryExhausted builds the RetryExhausted error, its cause is the history itself
+// so unwrapping reaches every attempt error
+func retryExhausted(history RetryHistory) *Error {
+	err := New(RetryExhausted).WithArgs(len(history))
+	setMeta(err, MetaAttempts, len(history))
+	setMeta(err, MetaRetryHistory, history)
+	return err.With(history).Render()
+}
@@ -0,0 +1,157 @@
+package fail
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetryOption configures a RetryConfig built by NewRetryConfig
+// Options return a non-nil error when given an invalid value
+type RetryOption func(*RetryConfig) error
+
+// NewRetryConfig builds a RetryConfig from functional options
+// Defaults match the global config: 5 attempts, IsRetryableDefault and no delay
+// Returns a RetryConfigInvalid error if any option is invalid
+//
+// Example:
+//
+//	cfg, err := fail.NewRetryConfig(
+//		fail.WithMaxAttempts(4),
+//		fail.WithBackoff(fail.BackoffConstant(500*time.Millisecond)),
+//	)
+func NewRetryConfig(opts ...RetryOption) (RetryConfig, error) {
+	cfg := RetryConfig{
+		MaxAttempts:        5,
+		ShouldRetry:        IsRetryableDefault,
+		maxAttemptsDefault: true,
+		delayDefault:       true,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(&cfg); err != nil {
+			return RetryConfig{}, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// MustNewRetryConfig is like NewRetryConfig but panics on invalid options
+// Useful for package level configs
+func MustNewRetryConfig(opts ...RetryOption) RetryConfig {
+	cfg, err := NewRetryConfig(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// WithMaxAttempts sets the total number of attempts, including the first call (must be >= 1)
+func WithMaxAttempts(n int) RetryOption {
+	return func(c *RetryConfig) error {
+		if n < 1 {
+			return invalidRetryOption("WithMaxAttempts", fmt.Sprintf("max attempts must be >= 1, got %d", n))
+		}
+		c.MaxAttempts = n
+		c.maxAttemptsDefault = false
+		return nil
+	}
+}
+
+// WithBackoff sets the delay function used between attempts (e.g., BackoffExponential)
+func WithBackoff(delay func(attempt int) time.Duration) RetryOption {
+	return func(c *RetryConfig) error {
+		if delay == nil {
+			return invalidRetryOption("WithBackoff", "backoff function must not be nil")
+		}
+		c.Delay = delay
+		c.delayDefault = false
+		return nil
+	}
+}
+
+// WithShouldRetry sets the predicate deciding if an error is retryable
+func WithShouldRetry(fn func(error) bool) RetryOption {
+	return func(c *RetryConfig) error {
+		if fn == nil {
+			return invalidRetryOption("WithShouldRetry", "should retry predicate must not be nil")
+		}
+		c.ShouldRetry = fn
+		return nil
+	}
+}
+
+// WithMaxElapsed sets the maximum total time spent retrying (0 = no limit)
+func WithMaxElapsed(d time.Duration) RetryOption {
+	return func(c *RetryConfig) error {
+		if d < 0 {
+			return invalidRetryOption("WithMaxElapsed", fmt.Sprintf("max elapsed must be >= 0, got %s", d))
+		}
+		c.MaxElapsed = d
+		return nil
+	}
+}
+
+// WithMaxDelay caps every single delay produced by the backoff (0 = no cap)
+func WithMaxDelay(d time.Duration) RetryOption {
+	return func(c *RetryConfig) error {
+		if d < 0 {
+			return invalidRetryOption("WithMaxDelay", fmt.Sprintf("max delay must be >= 0, got %s", d))
+		}
+		c.MaxDelay = d
+		return nil
+	}
+}
+
+// WithMaxRetryAfter caps retry_after hints carried by errors (0 = no cap)
+func WithMaxRetryAfter(d time.Duration) RetryOption {
+	return func(c *RetryConfig) error {
+		if d < 0 {
+			return invalidRetryOption("WithMaxRetryAfter", fmt.Sprintf("max retry after must be >= 0, got %s", d))
+		}
+		c.MaxRetryAfter = d
+		return nil
+	}
+}
+
+// WithOnAttempt adds a hook that runs after every attempt with its number and error (nil on success)
+// Can be passed multiple times, hooks run in the order they were added
+func WithOnAttempt(fn func(attempt int, err error)) RetryOption {
+	return func(c *RetryConfig) error {
+		if fn == nil {
+			return invalidRetryOption("WithOnAttempt", "attempt hook must not be nil")
+		}
+		c.OnAttempt = append(c.OnAttempt, fn)
+		return nil
+	}
+}
+
+// WithBudget shares a RetryBudget between every retry using this config
+func WithBudget(budget *RetryBudget) RetryOption {
+	return func(c *RetryConfig) error {
+		if budget == nil {
+			return invalidRetryOption("WithBudget", "retry budget must not be nil")
+		}
+		c.Budget = budget
+		return nil
+	}
+}
+
+// WithExhaustedError makes the retry return a RetryExhausted error with the full attempt history
+// instead of only the last error once attempts run out
+func WithExhaustedError(enabled bool) RetryOption {
+	return func(c *RetryConfig) error {
+		c.ExhaustedError = enabled
+		return nil
+	}
+}
+
+func invalidRetryOption(option, reason string) *Error {
+	return New(RetryConfigInvalid).
+		WithArgs(reason).
+		AddMeta("option", option).
+		Render()
+}
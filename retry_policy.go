@@ -0,0 +1,81 @@
+package fail
+
+import (
+	"log"
+	"time"
+)
+
+// RetryPolicy declares how errors with a given ID are retried
+// It is registered once next to the ID and consulted by every Retry* call
+type RetryPolicy struct {
+	// Retryable is used by IsRetryableDefault when the error has no MetaRetryable
+	Retryable bool
+
+	// MaxAttempts is used when this error is returned and the caller didn't set
+	// RetryConfig.MaxAttempts, an explicit per-call value always wins (0 = use config)
+	MaxAttempts int
+
+	// Delay is used when this error is returned and the caller didn't set
+	// RetryConfig.Delay, an explicit backoff always wins (nil = use config)
+	Delay func(attempt int) time.Duration
+}
+
+// SetRetryPolicy registers a retry policy for an ID on the global registry
+//
+// Example:
+//
+//	fail.SetRetryPolicy(DBDeadlock, fail.RetryPolicy{
+//		Retryable:   true,
+//		MaxAttempts: 3,
+//		Delay:       fail.BackoffExponential(50 * time.Millisecond),
+//	})
+func SetRetryPolicy(id ErrorID, policy RetryPolicy) *Error {
+	return global.SetRetryPolicy(id, policy)
+}
+
+// SetRetryPolicy registers a retry policy for an ID on this registry
+// Setting a policy again for the same ID replaces it
+func (r *Registry) SetRetryPolicy(id ErrorID, policy RetryPolicy) *Error {
+	// Verify the ErrorID is trusted
+	if !id.IsRegistered() {
+		if r.allowInternalLogs {
+			log.Printf("cannot set retry policy for unregistered ID: %s (must use fail.ID() to create)\n", id)
+		}
+		return New(UnregisteredIDError).WithArgs(id).Render()
+	}
+
+	if policy.MaxAttempts < 0 {
+		policy.MaxAttempts = 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.retryPolicies == nil {
+		r.retryPolicies = make(map[ErrorID]RetryPolicy)
+	}
+	r.retryPolicies[id] = policy
+	return nil
+}
+
+// GetRetryPolicy returns the retry policy registered for an ID on the global registry
+func GetRetryPolicy(id ErrorID) (RetryPolicy, bool) {
+	return global.GetRetryPolicy(id)
+}
+
+// GetRetryPolicy returns the retry policy registered for an ID on this registry
+func (r *Registry) GetRetryPolicy(id ErrorID) (RetryPolicy, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	policy, ok := r.retryPolicies[id]
+	return policy, ok
+}
+
+// RetryPolicy returns the retry policy registered for this error's ID in its registry
+func (e *Error) RetryPolicy() (RetryPolicy, bool) {
+	reg := e.registry
+	if reg == nil {
+		reg = global
+	}
+	return reg.GetRetryPolicy(e.ID)
+}
@@ -0,0 +1,116 @@
+package fail
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the number of program counters captured per error
+const maxStackDepth = 32
+
+// libraryPackage prefixes the functions of this package, they are dropped from stack traces
+var libraryPackage = reflect.TypeOf(Error{}).PkgPath() + "."
+
+// CaptureStackAtLevel enables stack capture on the global registry for every ID whose
+// Level() is at least minLevel, a negative minLevel disables level based capture
+// See Registry.CaptureStackAtLevel
+func CaptureStackAtLevel(minLevel int) {
+	global.CaptureStackAtLevel(minLevel)
+}
+
+// CaptureStackAtLevel enables stack capture for every ID whose Level() is at least
+// minLevel, a negative minLevel disables level based capture (default)
+//
+// Only program counters are recorded when the error is created by New, Wrap or a
+// mapper in From, frames are resolved on the first StackTrace call. Keep minLevel
+// above the level of domain errors created on hot paths.
+//
+// Example:
+//
+//	fail.CaptureStackAtLevel(2) // capture for errors, not for expected domain errors
+func (r *Registry) CaptureStackAtLevel(minLevel int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.captureStackLevel = minLevel
+	r.captureStackByLevel = minLevel >= 0
+}
+
+// CaptureStackForID enables or disables stack capture for a single ID on the global registry
+// See Registry.CaptureStackForID
+func CaptureStackForID(id ErrorID, capture bool) {
+	global.CaptureStackForID(id, capture)
+}
+
+// CaptureStackForID enables or disables stack capture for a single ID
+// Per ID settings override CaptureStackAtLevel
+func (r *Registry) CaptureStackForID(id ErrorID, capture bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.captureStackIDs == nil {
+		r.captureStackIDs = make(map[ErrorID]bool)
+	}
+	r.captureStackIDs[id] = capture
+}
+
+// shouldCaptureStack reports whether errors with id capture their stack, must hold r.mu
+func (r *Registry) shouldCaptureStack(id ErrorID) bool {
+	if capture, ok := r.captureStackIDs[id]; ok {
+		return capture
+	}
+	return r.captureStackByLevel && id.Level() >= r.captureStackLevel
+}
+
+// callers records the program counters of the caller, frames are resolved lazily by StackTrace
+func callers(skip int) []uintptr {
+	pc := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+1, pc)
+	return pc[:n]
+}
+
+// StackTrace returns the stack captured when the error was created, nil if capture
+// wasn't enabled for its ID. Frames of this library are dropped
+func (e *Error) StackTrace() []Frame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(e.stack)
+	var out []Frame
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, libraryPackage) && f.Function != "" {
+			out = append(out, Frame{
+				Function: f.Function,
+				File:     f.File,
+				Line:     f.Line,
+				Package:  framePackage(f.Function),
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// GetStackTrace extracts the captured stack from an error
+func GetStackTrace(err error) ([]Frame, bool) {
+	if e, ok := As(err); ok {
+		if frames := e.StackTrace(); len(frames) > 0 {
+			return frames, true
+		}
+	}
+	return nil, false
+}
+
+// framePackage returns the import path of a fully qualified function name
+// e.g. "github.com/acme/app/users.(*Service).Get" -> "github.com/acme/app/users"
+func framePackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return function[:slash+1+dot]
+}
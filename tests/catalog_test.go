@@ -0,0 +1,199 @@
+package fail_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+	"github.com/MintzyG/fail/v3/plugins/localization"
+)
+
+var (
+	CatalogOrderMissing  = fail.ID(0, "CATALOG", 0, true, "CatalogOrderMissing")
+	CatalogStockReserved = fail.ID(0, "CATALOG", 1, true, "CatalogStockReserved")
+	CatalogGatewayDown   = fail.ID(2, "CATALOG", 0, false, "CatalogGatewayDown")
+	CatalogItemTooLarge  = fail.ID(1, "CATALOGITEM", 0, false, "CatalogItemTooLarge")
+)
+
+func newCatalogRegistry(t *testing.T, name string) *fail.Registry {
+	t.Helper()
+	reg := fail.MustNewRegistry(name)
+	reg.SetDefaultLocale("en-US")
+
+	reg.Form(CatalogOrderMissing, "order not found", false, nil).
+		AddLocalization("pt-BR", "pedido não encontrado")
+	reg.Form(CatalogStockReserved, "stock | reserved", false, map[string]any{"retryable": false, "team": "inventory"})
+	reg.Form(CatalogGatewayDown, "gateway %s unavailable", true, nil, "payments")
+	reg.Form(CatalogItemTooLarge, "item <b>%d</b> too large", false, nil, 10)
+
+	_ = reg.SetRetryPolicy(CatalogGatewayDown, fail.RetryPolicy{Retryable: true, MaxAttempts: 5})
+	return reg
+}
+
+func TestCatalog_Entries(t *testing.T) {
+	reg := newCatalogRegistry(t, "catalog-entries")
+	reg.SetLocalizer(localization.New())
+	reg.RegisterLocalizations("es-ES", map[fail.ErrorID]string{CatalogGatewayDown: "pasarela %s no disponible"})
+
+	catalog := reg.ExportCatalog()
+
+	if catalog.Registry != "catalog-entries" || catalog.DefaultLocale != "en-US" {
+		t.Errorf("Unexpected catalog header: %+v", catalog)
+	}
+	if got := strings.Join(catalog.Locales, ","); got != "en-US,es-ES,pt-BR" {
+		t.Errorf("Expected locales from the localizer and the default locale, got %s", got)
+	}
+
+	var ids []string
+	for _, e := range catalog.Entries {
+		ids = append(ids, e.ID)
+	}
+	if got := strings.Join(ids, ","); got != "0_CATALOG_0000_S,0_CATALOG_0001_S,2_CATALOG_0000_D,1_CATALOGITEM_0000_D" {
+		t.Errorf("Expected entries sorted by domain, type and number, got %s", got)
+	}
+
+	gateway := catalog.Entries[2]
+	if !gateway.System || !gateway.Retryable || gateway.MaxAttempts != 5 {
+		t.Errorf("Expected system retryable entry from the retry policy, got %+v", gateway)
+	}
+	if len(gateway.DefaultArgs) != 1 || gateway.DefaultArgs[0] != "payments" {
+		t.Errorf("Expected default args, got %v", gateway.DefaultArgs)
+	}
+	if gateway.Localizations["es-ES"] != "pasarela %s no disponible" {
+		t.Errorf("Expected es-ES translation, got %v", gateway.Localizations)
+	}
+
+	stock := catalog.Entries[1]
+	if stock.Retryable || stock.Meta["team"] != "inventory" {
+		t.Errorf("Expected default meta, got %+v", stock)
+	}
+	if catalog.Entries[0].Localizations["pt-BR"] != "pedido não encontrado" {
+		t.Errorf("Expected pt-BR translation, got %v", catalog.Entries[0].Localizations)
+	}
+
+	if got := strings.Join(catalog.Domains(), ","); got != "CATALOG,CATALOGITEM" {
+		t.Errorf("Unexpected domains %s", got)
+	}
+}
+
+func TestCatalog_PendingLocalizationsAndExplicitLocales(t *testing.T) {
+	reg := newCatalogRegistry(t, "catalog-pending") // no localizer, translations are pending
+
+	catalog := reg.ExportCatalog()
+	if got := strings.Join(catalog.Locales, ","); got != "en-US,pt-BR" {
+		t.Errorf("Expected pending locales, got %s", got)
+	}
+	if catalog.Entries[0].Localizations["pt-BR"] != "pedido não encontrado" {
+		t.Errorf("Expected pending translation, got %v", catalog.Entries[0].Localizations)
+	}
+
+	catalog = reg.ExportCatalog("fr-FR")
+	if len(catalog.Locales) != 1 || catalog.Entries[0].Localizations != nil {
+		t.Errorf("Explicit locales should restrict translations, got %v", catalog.Entries[0].Localizations)
+	}
+}
+
+func TestCatalog_JSON(t *testing.T) {
+	data, err := newCatalogRegistry(t, "catalog-json").ExportCatalog().JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Registry string `json:"registry"`
+		Entries  []struct {
+			ID            string            `json:"id"`
+			Name          string            `json:"name"`
+			Message       string            `json:"message"`
+			Retryable     bool              `json:"retryable"`
+			Localizations map[string]string `json:"localizations"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Registry != "catalog-json" || len(decoded.Entries) != 4 {
+		t.Fatalf("Unexpected JSON: %s", data)
+	}
+	first := decoded.Entries[0]
+	if first.ID != "0_CATALOG_0000_S" || first.Name != "CatalogOrderMissing" || first.Localizations["pt-BR"] == "" {
+		t.Errorf("Expected ID fields flattened next to the definition, got %+v", first)
+	}
+}
+
+func TestCatalog_Markdown(t *testing.T) {
+	md := string(newCatalogRegistry(t, "catalog-md").ExportCatalog().Markdown())
+
+	for _, want := range []string{
+		"# Error Catalog (catalog-md)",
+		"## CATALOG\n",
+		"## CATALOGITEM\n",
+		"| ID | Name | Message | System | Retryable | Meta | en-US | pt-BR |",
+		"| `0_CATALOG_0000_S` | CatalogOrderMissing | order not found | no | no |  |  | pedido não encontrado |",
+		`stock \| reserved`,
+		"retryable=false, team=inventory",
+		"| `2_CATALOG_0000_D` | CatalogGatewayDown | gateway %s unavailable | yes | yes |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected %q in markdown:\n%s", want, md)
+		}
+	}
+}
+
+func TestCatalog_HTML(t *testing.T) {
+	page, err := newCatalogRegistry(t, "catalog-html").ExportCatalog().HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(page)
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>Error Catalog (catalog-html)</title>",
+		"<h2>CATALOGITEM</h2>",
+		"<code>0_CATALOG_0000_S</code>",
+		"<td>pedido não encontrado</td>",
+		"item &lt;b&gt;%d&lt;/b&gt; too large", // messages are escaped
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in page", want)
+		}
+	}
+	if strings.Contains(html, "<link") || strings.Contains(html, "src=") {
+		t.Error("Page must be self-contained")
+	}
+}
+
+func TestCatalog_ConcurrentPendingLocalizations(t *testing.T) {
+	reg := newCatalogRegistry(t, "catalog-race") // no localizer, translations stay pending
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			reg.RegisterLocalizations(fmt.Sprintf("x-%d", i), map[fail.ErrorID]string{CatalogOrderMissing: "missing"})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			_ = reg.ExportCatalog()
+		}
+	}()
+	wg.Wait()
+}
+
+func TestCatalog_DefaultArgsFromDefinition(t *testing.T) {
+	reg := newCatalogRegistry(t, "catalog-args")
+	// The template is first-register-wins, the definition follows the last Form
+	reg.Form(CatalogGatewayDown, "gateway %s unavailable", true, nil, "billing")
+
+	gateway := reg.ExportCatalog().Entries[2]
+	if len(gateway.DefaultArgs) != 1 || gateway.DefaultArgs[0] != "billing" {
+		t.Errorf("Expected default args from the definition, got %v", gateway.DefaultArgs)
+	}
+}
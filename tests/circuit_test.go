@@ -0,0 +1,157 @@
+package fail_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	CircuitDownID  = fail.ID(0, "CIRCUIT", 0, true, "CircuitDownstreamDown")
+	CircuitInputID = fail.ID(0, "CIRCUIT", 1, true, "CircuitBadInput")
+	circuitNow     = time.Unix(0, 0)
+	circuitClock   = func() time.Time { return circuitNow }
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: CircuitDownID, DefaultMessage: "down", IsSystem: true})
+	fail.Register(fail.ErrorDefinition{ID: CircuitInputID, DefaultMessage: "bad input"})
+}
+
+func TestCircuitBreaker_Lifecycle(t *testing.T) {
+	var transitions []string
+	fail.OnCircuitStateChange(func(name string, from, to fail.CircuitState) {
+		if name == "lifecycle" {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		}
+	})
+
+	cb := fail.NewCircuitBreaker(fail.CircuitBreakerConfig{
+		Name:             "lifecycle",
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+		Now:              circuitClock,
+	})
+
+	down := func() error { return fail.New(CircuitDownID) }
+	ok := func() error { return nil }
+
+	// Domain errors are not counted
+	_ = cb.Execute(func() error { return fail.New(CircuitInputID) })
+	_ = cb.Execute(down)
+	if cb.State() != fail.CircuitStateClosed {
+		t.Fatalf("Expected closed after 1 counted failure, got %s", cb.State())
+	}
+	_ = cb.Execute(down)
+	if cb.State() != fail.CircuitStateOpen {
+		t.Fatalf("Expected open after threshold, got %s", cb.State())
+	}
+
+	called := false
+	err := cb.Execute(func() error { called = true; return nil })
+	if called || !fail.Is(err, fail.CircuitOpen) {
+		t.Fatalf("Open circuit should reject without calling, got %v", err)
+	}
+	if d, ok := fail.GetRetryAfter(err); !ok || d != time.Minute {
+		t.Errorf("Expected retry_after of remaining open time, got %v", d)
+	}
+
+	// Half-open after timeout, a trial failure re-opens
+	circuitNow = circuitNow.Add(time.Minute)
+	if cb.State() != fail.CircuitStateHalfOpen {
+		t.Fatalf("Expected half-open after timeout, got %s", cb.State())
+	}
+	_ = cb.Execute(down)
+	if cb.State() != fail.CircuitStateOpen {
+		t.Fatalf("Expected open after failed trial, got %s", cb.State())
+	}
+
+	circuitNow = circuitNow.Add(time.Minute)
+	if err := cb.Execute(ok); err != nil {
+		t.Fatalf("Trial call failed: %v", err)
+	}
+	if cb.State() != fail.CircuitStateClosed {
+		t.Fatalf("Expected closed after successful trial, got %s", cb.State())
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("Expected transitions %v, got %v", want, transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("Transition %d: want %s got %s", i, want[i], transitions[i])
+		}
+	}
+}
+
+func TestCircuitBreaker_CountIDs(t *testing.T) {
+	cb := fail.NewCircuitBreaker(fail.CircuitBreakerConfig{
+		Name:             "ids",
+		FailureThreshold: 1,
+		Counts:           fail.CountIDs(CircuitInputID),
+		Now:              circuitClock,
+	})
+
+	_ = cb.Execute(func() error { return errors.New("generic") })
+	if cb.State() != fail.CircuitStateClosed {
+		t.Error("Errors outside CountIDs should not trip the breaker")
+	}
+
+	v, err := fail.ExecuteValue(cb, func() (int, error) { return 0, fail.New(CircuitInputID) })
+	if v != 0 || !fail.Is(err, CircuitInputID) {
+		t.Errorf("ExecuteValue should pass the error through, got %v", err)
+	}
+	if cb.State() != fail.CircuitStateOpen {
+		t.Error("Counted ID should trip the breaker")
+	}
+
+	cb.Reset()
+	if cb.State() != fail.CircuitStateClosed {
+		t.Error("Reset should close the breaker")
+	}
+}
+
+func TestCircuitBreaker_PanicIsFailure(t *testing.T) {
+	now := time.Unix(0, 0)
+	cb := fail.NewCircuitBreaker(fail.CircuitBreakerConfig{
+		Name:             "panics",
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+		Now:              func() time.Time { return now },
+	})
+
+	mustPanic := func(fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected the panic to propagate")
+			}
+		}()
+		fn()
+	}
+
+	mustPanic(func() { _ = cb.Execute(func() error { panic("boom") }) })
+	if cb.State() != fail.CircuitStateOpen {
+		t.Fatalf("Expected a panic to count as a failure, got %s", cb.State())
+	}
+
+	// A panicking half-open trial must release its slot and re-open
+	now = now.Add(time.Minute)
+	mustPanic(func() {
+		_, _ = fail.ExecuteValue(cb, func() (int, error) { panic("boom") })
+	})
+	if cb.State() != fail.CircuitStateOpen {
+		t.Fatalf("Expected open after a panicking trial, got %s", cb.State())
+	}
+
+	now = now.Add(time.Minute)
+	if err := cb.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("Breaker wedged after a panic: %v", err)
+	}
+	if cb.State() != fail.CircuitStateClosed {
+		t.Errorf("Expected closed after a successful trial, got %s", cb.State())
+	}
+}
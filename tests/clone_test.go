@@ -0,0 +1,100 @@
+package fail_test
+
+import (
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	CloneSignupInvalid = fail.ID(0, "CLONE", 0, false, "CloneSignupInvalid")
+	CloneStoreFailed   = fail.ID(0, "CLONE", 1, false, "CloneStoreFailed")
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: CloneSignupInvalid, DefaultMessage: "signup invalid for %v"})
+	fail.Register(fail.ErrorDefinition{ID: CloneStoreFailed, DefaultMessage: "store failed"})
+}
+
+type cloneTags struct{ items []string }
+
+func (c *cloneTags) Clone() any {
+	return &cloneTags{items: append([]string(nil), c.items...)}
+}
+
+func TestDeepClone_MetaAndArgs(t *testing.T) {
+	orig := fail.New(CloneSignupInvalid).
+		WithArgs([]any{"bob"}).
+		Validation("email", "required").
+		Trace("signup").
+		AddMeta("tags", &cloneTags{items: []string{"a"}}).
+		AddMeta("nested", map[string]any{"list": []string{"x"}})
+
+	clone := orig.DeepClone(false).
+		Validation("password", "too short").
+		Trace("retry")
+	clone.Args[0].([]any)[0] = "alice"
+	clone.Meta["tags"].(*cloneTags).items[0] = "changed"
+	clone.Meta["nested"].(map[string]any)["list"].([]string)[0] = "changed"
+
+	if v, _ := fail.GetValidations(orig); len(v) != 1 {
+		t.Errorf("Original validations corrupted: %v", v)
+	}
+	if v, _ := fail.GetTraces(orig); len(v) != 1 {
+		t.Errorf("Original traces corrupted: %v", v)
+	}
+	if orig.Args[0].([]any)[0] != "bob" {
+		t.Error("Original args corrupted")
+	}
+	if orig.Meta["tags"].(*cloneTags).items[0] != "a" {
+		t.Error("Cloner values must be copied through Clone")
+	}
+	if orig.Meta["nested"].(map[string]any)["list"].([]string)[0] != "x" {
+		t.Error("Nested maps must be copied recursively")
+	}
+	if v, _ := fail.GetValidations(clone); len(v) != 2 {
+		t.Errorf("Clone lost validations: %v", v)
+	}
+}
+
+func TestDeepClone_Cause(t *testing.T) {
+	cause := fail.New(CloneStoreFailed).AddMeta("table", "users")
+	orig := fail.New(CloneSignupInvalid).With(cause)
+
+	shallow := orig.DeepClone(false)
+	if shallow.Cause != cause {
+		t.Error("Cause must be shared when cloneCause is false")
+	}
+
+	deep := orig.DeepClone(true)
+	deepCause, ok := deep.Cause.(*fail.Error)
+	if !ok || deepCause == cause {
+		t.Fatal("Cause must be deep cloned when cloneCause is true")
+	}
+	deepCause.Meta["table"] = "orders"
+	if cause.Meta["table"] != "users" {
+		t.Error("Original cause corrupted")
+	}
+	if !fail.Is(deep, CloneSignupInvalid) || !fail.Is(deep.Cause, CloneStoreFailed) {
+		t.Error("IDs must survive the clone")
+	}
+}
+
+func TestDeepClone_LibraryErrorCollections(t *testing.T) {
+	group := fail.NewErrorGroup(2)
+	group.Add(fail.New(CloneSignupInvalid).WithArgs("bob"))
+	group.Add(fail.New(CloneStoreFailed))
+	orig := group.ToError().
+		AddMeta("failures", map[string]*fail.Error{"signup": fail.New(CloneSignupInvalid).WithArgs("bob")})
+
+	clone := orig.DeepClone(false)
+	clone.Meta["errors"].([]*fail.Error)[0].AddMeta("touched", true)
+	clone.Meta["failures"].(map[string]*fail.Error)["signup"].AddMeta("touched", true)
+
+	if _, ok := fail.GetMeta(orig.Meta["errors"].([]*fail.Error)[0], "touched"); ok {
+		t.Error("[]*Error meta must be deep cloned")
+	}
+	if _, ok := fail.GetMeta(orig.Meta["failures"].(map[string]*fail.Error)["signup"], "touched"); ok {
+		t.Error("map[string]*Error meta must be deep cloned")
+	}
+}
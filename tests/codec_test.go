@@ -0,0 +1,119 @@
+package fail_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	CodecUserNotFound = fail.ID(0, "CODEC", 0, false, "CodecUserNotFound")
+	CodecDBDown       = fail.ID(0, "CODEC", 0, true, "CodecDatabaseDown")
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: CodecUserNotFound, DefaultMessage: "user %s not found"})
+	fail.Register(fail.ErrorDefinition{ID: CodecDBDown, DefaultMessage: "database unavailable", IsSystem: true})
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec := fail.NewCodec(fail.CodecConfig{MetaKeys: []string{"request_id", "attempt"}})
+
+	orig := fail.New(CodecUserNotFound).
+		WithArgs("bob").
+		Internal("select * from users where name = 'bob'").
+		AddMeta("request_id", "req-1").
+		AddMeta("attempt", 3).
+		AddMeta("password_hash", "do-not-leak").
+		RetryAfter(2 * time.Second).
+		With(fmt.Errorf("lookup: %w", fail.New(CodecDBDown)))
+
+	header := codec.EncodeString(orig)
+	if strings.ContainsAny(header, "+/=\n") {
+		t.Errorf("Encoded form must be header-safe, got %q", header)
+	}
+
+	got, err := codec.DecodeString(header)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if !fail.Is(got, CodecUserNotFound) || !got.IsRegistered() {
+		t.Errorf("Expected registered CodecUserNotFound, got %v", got.ID)
+	}
+	if got.GetRendered() != "user bob not found" {
+		t.Errorf("Unexpected rendered message %q", got.GetRendered())
+	}
+	if got.InternalMessage != "" {
+		t.Error("Internal message must be stripped")
+	}
+	if v, _ := fail.GetMeta(got, "request_id"); v != "req-1" {
+		t.Errorf("Allowed meta lost: %v", v)
+	}
+	if v, _ := fail.GetMeta(got, "attempt"); v != 3 {
+		t.Errorf("Int meta lost: %v", v)
+	}
+	if _, ok := fail.GetMeta(got, "password_hash"); ok {
+		t.Error("Meta outside the allowlist must not cross the boundary")
+	}
+	if d, ok := fail.GetRetryAfter(got); !ok || d != 2*time.Second {
+		t.Errorf("retry_after should always cross, got %v", d)
+	}
+
+	// fail.Is sees through the restored generic wrapper
+	if !fail.Is(errors.Unwrap(got.Cause), CodecDBDown) {
+		t.Errorf("Expected nested CodecDBDown, got %v", got.Cause)
+	}
+	if got.Cause.Error() != "lookup: "+fail.New(CodecDBDown).Error() {
+		t.Errorf("Generic cause message lost: %v", got.Cause)
+	}
+}
+
+func TestCodec_LibraryMetaKeepsTypes(t *testing.T) {
+	// Library keys are allowed by their plain names
+	codec := fail.NewCodec(fail.CodecConfig{MetaKeys: []string{"attempts", fail.MetaRetryBudgetTokens.Name()}})
+
+	orig := fail.New(CodecUserNotFound).WithArgs("bob").RetryAfter(time.Second)
+	_ = fail.SetMeta(orig, fail.MetaRetryable, true)
+	_ = fail.SetMeta(orig, fail.MetaAttempts, 3)
+	_ = fail.SetMeta(orig, fail.MetaRetryBudgetTokens, 1.5)
+
+	got, err := codec.Decode(codec.Encode(orig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := fail.GetRetryAfter(got); !ok || d != time.Second || !fail.IsRetryableDefault(got) {
+		t.Errorf("Retry hints lost their types: %v", got.Meta)
+	}
+	if n, ok := fail.GetMetaAs(got, fail.MetaAttempts); !ok || n != 3 {
+		t.Errorf("MetaAttempts after codec = %v, %v", n, ok)
+	}
+	if f, ok := fail.GetMetaAs(got, fail.MetaRetryBudgetTokens); !ok || f != 1.5 {
+		t.Errorf("MetaRetryBudgetTokens after codec = %v, %v", f, ok)
+	}
+}
+
+func TestCodec_DecodeInvalid(t *testing.T) {
+	codec := fail.NewCodec(fail.CodecConfig{})
+	valid := codec.Encode(fail.New(CodecDBDown))
+
+	cases := map[string][]byte{
+		"empty":     nil,
+		"version":   append([]byte{99}, valid[1:]...),
+		"truncated": valid[:len(valid)-2],
+		"trailing":  append(append([]byte{}, valid...), 0),
+	}
+	for name, data := range cases {
+		if _, err := codec.Decode(data); !fail.Is(err, fail.ErrorPayloadInvalid) {
+			t.Errorf("%s: expected ErrorPayloadInvalid, got %v", name, err)
+		}
+	}
+
+	if _, err := codec.DecodeString("!!!"); !fail.Is(err, fail.ErrorPayloadInvalid) {
+		t.Errorf("Expected ErrorPayloadInvalid for bad base64, got %v", err)
+	}
+}
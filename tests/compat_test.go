@@ -0,0 +1,203 @@
+package fail_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+func compatEntry(level int, domain string, number int, static bool, name string) fail.IDListEntry {
+	kind := "D"
+	if static {
+		kind = "S"
+	}
+	return fail.IDListEntry{
+		Name:   name,
+		Domain: domain,
+		Static: static,
+		Level:  level,
+		Number: number,
+		ID:     fmt.Sprintf("%d_%s_%04d_%s", level, domain, number, kind),
+	}
+}
+
+func compatList(t *testing.T, entries ...fail.IDListEntry) []byte {
+	t.Helper()
+	data, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func compatKinds(report *fail.CompatReport) map[fail.CompatChangeKind][]fail.CompatChange {
+	out := make(map[fail.CompatChangeKind][]fail.CompatChange)
+	for _, c := range report.Changes {
+		out[c.Kind] = append(out[c.Kind], c)
+	}
+	return out
+}
+
+func TestCompat_NoChanges(t *testing.T) {
+	list := compatList(t,
+		compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials"),
+		compatEntry(0, "AUTH", 1, true, "AuthTokenExpired"),
+	)
+
+	report, err := fail.CompareIDLists(list, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changes) != 0 || report.Breaking() {
+		t.Errorf("Expected no changes, got %v", report.Changes)
+	}
+}
+
+func TestCompat_AddedIsNonBreaking(t *testing.T) {
+	old := compatList(t, compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials"))
+	current := compatList(t,
+		compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials"),
+		compatEntry(0, "AUTH", 1, true, "AuthTokenExpired"),
+	)
+
+	report, err := fail.CompareIDLists(old, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Breaking() {
+		t.Errorf("Additions must not be breaking: %v", report.Changes)
+	}
+	added := compatKinds(report)[fail.CompatAdded]
+	if len(added) != 1 || added[0].New.Name != "AuthTokenExpired" {
+		t.Errorf("Expected AuthTokenExpired added, got %v", report.Changes)
+	}
+}
+
+func TestCompat_BreakingChanges(t *testing.T) {
+	old := compatList(t,
+		compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials"),
+		compatEntry(0, "AUTH", 1, true, "AuthTokenExpired"),
+		compatEntry(0, "AUTH", 2, true, "AuthAccountLocked"),
+		compatEntry(0, "AUTH", 0, false, "AuthProviderDown"),
+		compatEntry(0, "AUTH", 1, false, "AuthSessionRevoked"),
+		compatEntry(1, "USER", 0, false, "UserNotFound"),
+	)
+	current := compatList(t,
+		compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials"),
+		compatEntry(0, "AUTH", 1, true, "AuthAccountLocked"),  // renumbered 2 -> 1 (AuthTokenExpired removed)
+		compatEntry(2, "AUTH", 0, false, "AuthProviderDown"),  // level 0 -> 2
+		compatEntry(0, "AUTH", 2, true, "AuthSessionRevoked"), // dynamic -> static
+		compatEntry(1, "ACCOUNT", 0, false, "UserNotFound"),   // domain move
+	)
+
+	report, err := fail.CompareIDLists(old, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Breaking() {
+		t.Fatal("Expected breaking changes")
+	}
+
+	byKind := compatKinds(report)
+	expect := map[fail.CompatChangeKind]string{
+		fail.CompatRemoved:       "AuthTokenExpired",
+		fail.CompatRenumbered:    "AuthAccountLocked",
+		fail.CompatLevelChanged:  "AuthProviderDown",
+		fail.CompatStaticFlipped: "AuthSessionRevoked",
+		fail.CompatDomainMoved:   "UserNotFound",
+	}
+	for kind, name := range expect {
+		changes := byKind[kind]
+		if len(changes) != 1 || changes[0].Old.Name != name || !changes[0].Breaking {
+			t.Errorf("Expected breaking %s for %s, got %v", kind, name, changes)
+		}
+	}
+	if len(report.BreakingChanges()) != len(report.Changes) {
+		t.Errorf("Every change should be breaking: %v", report.Changes)
+	}
+}
+
+func TestCompat_Renamed(t *testing.T) {
+	old := compatList(t, compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials"))
+	current := compatList(t, compatEntry(0, "AUTH", 0, true, "AuthBadCredentials"))
+
+	report, err := fail.CompareIDLists(old, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changes) != 1 {
+		t.Fatalf("Expected a single rename, got %v", report.Changes)
+	}
+
+	c := report.Changes[0]
+	// The name is the Go identifier and what LookupByName resolves
+	if c.Kind != fail.CompatRenamed || !c.Breaking || !report.Breaking() {
+		t.Errorf("Expected breaking rename, got %v", c)
+	}
+	if got := c.String(); got != "breaking: AuthInvalidCredentials renamed to AuthBadCredentials (0_AUTH_0000_S)" {
+		t.Errorf("Unexpected description: %s", got)
+	}
+}
+
+func TestCompat_ChangeString(t *testing.T) {
+	old := compatList(t,
+		compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials"),
+		compatEntry(0, "AUTH", 1, true, "AuthTokenExpired"),
+	)
+	current := compatList(t, compatEntry(0, "AUTH", 0, true, "AuthTokenExpired"))
+
+	report, err := fail.CompareIDLists(old, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for _, c := range report.Changes {
+		lines = append(lines, c.String())
+	}
+	want := []string{
+		"breaking: AuthInvalidCredentials (0_AUTH_0000_S) removed",
+		"breaking: AuthTokenExpired renumbered 0_AUTH_0001_S -> 0_AUTH_0000_S",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected report:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestCompat_ExportIDListRoundTrip(t *testing.T) {
+	current, err := fail.ExportIDList()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := fail.CompareIDLists(current, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changes) != 0 {
+		t.Errorf("Catalog compared with itself should have no changes, got %d", len(report.Changes))
+	}
+}
+
+func TestCompat_InvalidCatalog(t *testing.T) {
+	valid := compatList(t, compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials"))
+	duplicated := compatList(t,
+		compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials"),
+		compatEntry(0, "AUTH", 1, true, "AuthInvalidCredentials"),
+	)
+
+	for name, data := range map[string][]byte{
+		"malformed":  []byte("{"),
+		"duplicated": duplicated,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := fail.CompareIDLists(valid, data)
+			if !fail.Is(err, fail.CatalogInvalid) {
+				t.Errorf("Expected CatalogInvalid, got %v", err)
+			}
+		})
+	}
+}
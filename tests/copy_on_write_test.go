@@ -0,0 +1,118 @@
+package fail_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	CowOrderMissing = fail.ID(0, "COW", 0, false, "CowOrderMissing")
+	CowQuotaFull    = fail.ID(0, "COW", 0, true, "CowQuotaFull")
+)
+
+func TestCopyOnWrite_Sentinels(t *testing.T) {
+	reg := fail.MustNewRegistry("cow-sentinels")
+	reg.SetCopyOnWrite(true)
+	sentinel := reg.Form(CowOrderMissing, "order %s missing", false, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := sentinel.WithArgs(i).AddMeta("worker", i).Render()
+			if err == sentinel {
+				t.Error("Builders on a sentinel must return a clone")
+			}
+			if v, _ := fail.GetMeta(err, "worker"); v != i {
+				t.Errorf("Clone lost its own meta: %v", v)
+			}
+			if !fail.Is(err, CowOrderMissing) {
+				t.Error("Clone must keep the ID")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(sentinel.Meta) != 0 || len(sentinel.Args) != 0 || sentinel.Message != "order %s missing" {
+		t.Errorf("Sentinel was mutated: %+v", sentinel.Dump())
+	}
+
+	// A clone is owned by the caller, further builders modify it in place
+	clone := sentinel.AddMeta("a", 1)
+	if clone.AddMeta("b", 2) != clone {
+		t.Error("Builders on an owned clone must not clone again")
+	}
+}
+
+func TestCopyOnWrite_Static(t *testing.T) {
+	reg := fail.MustNewRegistry("cow-static")
+	reg.SetCopyOnWrite(true)
+	sentinel := reg.Form(CowQuotaFull, "quota full", false, nil)
+
+	err := sentinel.AddMeta("tenant", "acme").Msg("overridden").System()
+	if err == sentinel {
+		t.Fatal("Allowed builders on static errors must return a clone")
+	}
+	if v, _ := fail.GetMeta(err, "tenant"); v != "acme" {
+		t.Errorf("Allowed field not changed: %v", v)
+	}
+	if err.Message != "quota full" || err.IsSystem {
+		t.Errorf("Message and classification of static errors must stay locked: %q system=%v", err.Message, err.IsSystem)
+	}
+	if sentinel.Meta != nil {
+		t.Error("Static sentinel was mutated")
+	}
+}
+
+func TestCopyOnWrite_DisabledByDefault(t *testing.T) {
+	reg := fail.MustNewRegistry("cow-disabled")
+	sentinel := reg.Form(CowOrderMissing, "order missing", false, nil)
+
+	if sentinel.AddMeta("k", "v") != sentinel {
+		t.Error("Without copy-on-write builders mutate in place")
+	}
+}
+
+func TestCopyOnWrite_SliceMetaNotShared(t *testing.T) {
+	reg := fail.MustNewRegistry("cow-slices")
+	reg.SetCopyOnWrite(true)
+
+	// Spare capacity, appends on clones would write into the same backing array
+	traces := make([]string, 1, 8)
+	traces[0] = "declared"
+	sentinel := reg.Form(CowOrderMissing, "order missing", false, map[string]any{fail.MetaTraces.Name(): traces})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := sentinel.Trace(fmt.Sprintf("worker %d", i)).Debug("d").Validation("field", "required")
+			got, _ := fail.GetMetaAs(err, fail.MetaTraces)
+			if len(got) != 2 || got[1] != fmt.Sprintf("worker %d", i) {
+				t.Errorf("Clone traces = %v", got)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got, _ := fail.GetMetaAs(sentinel, fail.MetaTraces); len(got) != 1 {
+		t.Errorf("Sentinel traces were mutated: %v", got)
+	}
+}
+
+func TestCopyOnWrite_RegisterKeepsArgument(t *testing.T) {
+	reg := fail.MustNewRegistry("cow-register")
+	reg.SetCopyOnWrite(true)
+
+	def := &fail.Error{ID: CowOrderMissing, Message: "order missing"}
+	_ = reg.Register(def)
+
+	if def.AddMeta("k", "v") != def {
+		t.Error("Register must not mark the caller's error as shared")
+	}
+}
@@ -0,0 +1,244 @@
+package fail_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	DeprecLegacyLookup = fail.ID(0, "DEPREC", 0, true, "DeprecLegacyLookup")
+	DeprecUserLookup   = fail.ID(0, "DEPREC", 1, true, "DeprecUserLookup")
+	DeprecAccountFetch = fail.ID(0, "DEPREC", 2, true, "DeprecAccountFetch")
+	DeprecUnrelated    = fail.ID(0, "DEPREC", 3, true, "DeprecUnrelated")
+	DeprecSiblingA     = fail.ID(0, "DEPREC", 4, true, "DeprecOrderCancelled")
+	DeprecSiblingB     = fail.ID(0, "DEPREC", 5, true, "DeprecInvoiceVoided")
+	DeprecSiblingNew   = fail.ID(0, "DEPREC", 6, true, "DeprecRefundIssued")
+
+	errDeprecLegacyLookup = fail.Form(DeprecLegacyLookup, "legacy lookup failed", false, nil)
+	errDeprecUserLookup   = fail.Form(DeprecUserLookup, "user lookup failed", false, nil)
+	_                     = fail.Form(DeprecAccountFetch, "account fetch failed", false, nil)
+	_                     = fail.Form(DeprecUnrelated, "unrelated", false, nil)
+	_                     = fail.Form(DeprecSiblingA, "sibling a", false, nil)
+	_                     = fail.Form(DeprecSiblingB, "sibling b", false, nil)
+	_                     = fail.Form(DeprecSiblingNew, "sibling replacement", false, nil)
+
+	// DeprecAccountFetch -> DeprecLegacyLookup -> DeprecUserLookup
+	_ = fail.Deprecate(DeprecLegacyLookup, DeprecUserLookup, "lookups merged")
+	_ = fail.Deprecate(DeprecAccountFetch, DeprecLegacyLookup, "accounts are users now")
+	_ = fail.Deprecate(DeprecSiblingA, DeprecSiblingNew, "merged")
+	_ = fail.Deprecate(DeprecSiblingB, DeprecSiblingNew, "merged")
+)
+
+// Isolated ID registry, numbers of deprecated IDs must keep counting
+var (
+	deprecIDs         = fail.NewIDRegistry()
+	DeprecGapsFirst   = deprecIDs.ID(0, "GAPS", 0, true, "GapsFirstEntry")
+	DeprecGapsSecond  = deprecIDs.ID(0, "GAPS", 1, true, "GapsSecondEntry")
+	DeprecGapsThird   = deprecIDs.ID(0, "GAPS", 2, true, "GapsThirdEntry")
+	deprecGapsOutcome = deprecIDs.Deprecate(DeprecGapsFirst, DeprecGapsThird, "superseded")
+)
+
+// 19 IDs on their own ID registry, for the chain length limit (ChainAAAA, ChainBBBB, ...)
+var (
+	deprecChainIDs = fail.NewIDRegistry()
+	deprecChain    = func() []fail.ErrorID {
+		ids := make([]fail.ErrorID, 19)
+		for i := range ids {
+			ids[i] = deprecChainIDs.ID(0, "CHAIN", i, true, "Chain"+strings.Repeat(string(rune('A'+i)), 4))
+		}
+		return ids
+	}()
+)
+
+func TestDeprecation_GetDeprecation(t *testing.T) {
+	dep, ok := fail.GetDeprecation(DeprecLegacyLookup)
+	if !ok {
+		t.Fatal("Expected DeprecLegacyLookup to be deprecated")
+	}
+	if dep.ID != DeprecLegacyLookup || dep.Replacement != DeprecUserLookup || dep.Reason != "lookups merged" {
+		t.Errorf("Unexpected deprecation %+v", dep)
+	}
+	if _, ok := fail.GetDeprecation(DeprecUserLookup); ok {
+		t.Error("Replacement must not be deprecated")
+	}
+}
+
+func TestDeprecation_IsMatchesAliasAndReplacement(t *testing.T) {
+	old := fail.New(DeprecLegacyLookup)
+	current := fail.New(DeprecUserLookup)
+
+	if !fail.Is(old, DeprecUserLookup) || !fail.Is(current, DeprecLegacyLookup) {
+		t.Error("fail.Is must match alias and replacement both ways")
+	}
+	if !errors.Is(old, errDeprecUserLookup) || !errors.Is(current, errDeprecLegacyLookup) {
+		t.Error("errors.Is must match alias and replacement both ways")
+	}
+	if !errors.Is(old, fail.IDError(DeprecUserLookup)) {
+		t.Error("IDError target must match the alias")
+	}
+
+	// Chains resolve to the last replacement
+	chained := fail.New(DeprecAccountFetch)
+	if !fail.Is(chained, DeprecUserLookup) || !fail.Is(current, DeprecAccountFetch) {
+		t.Error("Aliases must follow the replacement chain")
+	}
+
+	if fail.Is(old, DeprecUnrelated) || errors.Is(fail.New(DeprecUnrelated), errDeprecUserLookup) {
+		t.Error("Unrelated IDs must not match")
+	}
+
+	if !fail.Match(old).Case(DeprecUserLookup, func(*fail.Error) {}).Matched() {
+		t.Error("Match must treat the alias as its replacement")
+	}
+}
+
+func TestDeprecation_SiblingsDoNotMatch(t *testing.T) {
+	a, b := fail.New(DeprecSiblingA), fail.New(DeprecSiblingB)
+
+	if !fail.Is(a, DeprecSiblingNew) || !fail.Is(b, DeprecSiblingNew) {
+		t.Error("Each alias must match the replacement")
+	}
+	if fail.Is(a, DeprecSiblingB) || fail.Is(b, DeprecSiblingA) {
+		t.Error("IDs deprecated into the same replacement must not match each other")
+	}
+	if fail.Match(a).Case(DeprecSiblingB, func(*fail.Error) {}).Matched() {
+		t.Error("Match must not treat siblings as aliases")
+	}
+}
+
+func TestDeprecation_ChainLengthLimit(t *testing.T) {
+	// 1 -> 2 -> ... -> 17 is 16 hops, the longest allowed chain
+	for i := 1; i < 17; i++ {
+		if err := deprecChainIDs.Deprecate(deprecChain[i], deprecChain[i+1], "next"); err != nil {
+			t.Fatalf("Deprecate %d: %v", i, err)
+		}
+	}
+
+	for name, err := range map[string]*fail.Error{
+		"extend the end":   deprecChainIDs.Deprecate(deprecChain[17], deprecChain[18], "next"),
+		"extend the start": deprecChainIDs.Deprecate(deprecChain[0], deprecChain[1], "next"),
+	} {
+		if !fail.Is(err, fail.IDDeprecationInvalid) || !strings.Contains(err.Error(), "at most 16") {
+			t.Errorf("%s: expected the chain to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestDeprecation_NewFiresHookAndLogs(t *testing.T) {
+	reg := fail.MustNewRegistry("deprecation-hooks")
+
+	var got []fail.Deprecation
+	reg.On(fail.HookDeprecated, func(err *fail.Error, dep fail.Deprecation) {
+		got = append(got, dep)
+	})
+
+	_ = reg.Form(DeprecLegacyLookup, "legacy lookup failed", false, nil)
+	_ = reg.Form(DeprecUserLookup, "user lookup failed", false, nil)
+	if len(got) != 0 {
+		t.Fatalf("Form must not fire the deprecation hook, got %v", got)
+	}
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	reg.AllowInternalLogs(true)
+
+	_ = reg.New(DeprecUserLookup)
+	_ = reg.New(DeprecLegacyLookup)
+
+	if len(got) != 1 || got[0].Replacement != DeprecUserLookup {
+		t.Errorf("Expected one deprecation hook call for the alias, got %v", got)
+	}
+	if !strings.Contains(logs.String(), "deprecated error ID 0_DEPREC_0000_S (DeprecLegacyLookup) created, use 0_DEPREC_0001_S") {
+		t.Errorf("Expected a deprecation log, got %q", logs.String())
+	}
+}
+
+func TestDeprecation_ExportIDListStatus(t *testing.T) {
+	data, err := fail.ExportIDList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []fail.IDListEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]fail.IDListEntry)
+	for _, e := range entries {
+		byName[e.Name] = e
+	}
+
+	legacy := byName["DeprecLegacyLookup"]
+	if legacy.Status != fail.IDStatusDeprecated || legacy.ReplacedBy != "0_DEPREC_0001_S" || legacy.DeprecationReason != "lookups merged" {
+		t.Errorf("Expected deprecated status, got %+v", legacy)
+	}
+	if byName["DeprecUserLookup"].Status != fail.IDStatusActive {
+		t.Errorf("Expected active status, got %+v", byName["DeprecUserLookup"])
+	}
+}
+
+func TestDeprecation_CustomIDRegistryKeepsNumbers(t *testing.T) {
+	if deprecGapsOutcome != nil {
+		t.Fatalf("Deprecate failed: %v", deprecGapsOutcome)
+	}
+
+	// Deprecated numbers keep counting, no gap
+	deprecIDs.ValidateIDs()
+
+	data, err := deprecIDs.ExportIDList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"replaced_by": "0_GAPS_0002_S"`) {
+		t.Errorf("Expected the custom registry export to list the deprecation:\n%s", data)
+	}
+	if _, ok := fail.GetDeprecation(DeprecGapsFirst); ok {
+		t.Error("Custom registry deprecations must not leak into the global registry")
+	}
+}
+
+func TestDeprecation_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		id, replace fail.ErrorID
+		want        string
+	}{
+		{"already deprecated", DeprecLegacyLookup, DeprecUnrelated, "already deprecated"},
+		{"cycle", DeprecUserLookup, DeprecAccountFetch, "resolves back to it"},
+		{"untrusted ID", fail.ErrorID{}, DeprecUserLookup, "not registered"},
+		{"untrusted replacement", DeprecUnrelated, fail.ErrorID{}, "not registered"},
+		{"other ID registry", DeprecUnrelated, DeprecGapsSecond, "not registered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fail.Deprecate(tt.id, tt.replace, "test")
+			if !fail.Is(err, fail.IDDeprecationInvalid) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected IDDeprecationInvalid containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDeprecation_CompatIsNonBreaking(t *testing.T) {
+	old := compatEntry(0, "AUTH", 0, true, "AuthInvalidCredentials")
+	current := old
+	current.Status = fail.IDStatusDeprecated
+	current.ReplacedBy = "0_AUTH_0001_S"
+
+	report := fail.CompareIDEntries([]fail.IDListEntry{old}, []fail.IDListEntry{current})
+	if len(report.Changes) != 1 || report.Changes[0].Kind != fail.CompatDeprecated || report.Breaking() {
+		t.Fatalf("Expected a non-breaking deprecation, got %v", report.Changes)
+	}
+	if got := report.Changes[0].String(); got != "non-breaking: AuthInvalidCredentials (0_AUTH_0000_S) deprecated, use 0_AUTH_0001_S" {
+		t.Errorf("Unexpected description: %s", got)
+	}
+}
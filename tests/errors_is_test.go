@@ -0,0 +1,60 @@
+package fail_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	StdIsUserMissing = fail.ID(0, "STDIS", 0, false, "StdisUserMissing")
+	StdIsTeamMissing = fail.ID(0, "STDIS", 1, false, "StdisTeamMissing")
+
+	errStdIsUserMissing = fail.Form(StdIsUserMissing, "user missing", false, nil)
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: StdIsTeamMissing, DefaultMessage: "team missing"})
+}
+
+func TestErrorsIs_ByID(t *testing.T) {
+	err := fmt.Errorf("handler: %w", fail.New(StdIsUserMissing).AddMeta("user", "bob"))
+
+	if !errors.Is(err, errStdIsUserMissing) {
+		t.Error("Distinct instances with the same ID must match")
+	}
+	if !errors.Is(err, fail.IDError(StdIsUserMissing)) {
+		t.Error("IDError target must match")
+	}
+	if errors.Is(err, fail.New(StdIsTeamMissing)) || errors.Is(err, fail.IDError(StdIsTeamMissing)) {
+		t.Error("Different IDs must not match")
+	}
+	if errors.Is(err, errors.New("user missing")) {
+		t.Error("Generic errors must not match")
+	}
+
+	// Deeper in the chain
+	wrapped := fail.New(StdIsTeamMissing).With(err)
+	if !errors.Is(wrapped, fail.IDError(StdIsUserMissing)) {
+		t.Error("errors.Is must walk the cause chain")
+	}
+}
+
+func TestErrorsIs_Untrusted(t *testing.T) {
+	data := []byte(`{"v":1,"id":"0_STDIS_0000_D","name":"StdisUserMissing","domain":"STDIS","number":0,"message":"x"}`)
+	registered, err := fail.UnmarshalError(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(registered, errStdIsUserMissing) {
+		t.Error("Errors re-hydrated from a registered ID must match")
+	}
+
+	data = []byte(`{"v":1,"id":"0_STDIS_0005_D","name":"StdisGhost","domain":"STDIS","number":5,"message":"x"}`)
+	untrusted, _ := fail.UnmarshalError(data)
+	if errors.Is(untrusted, untrusted.Clone()) {
+		t.Error("Untrusted IDs must never match by ID")
+	}
+}
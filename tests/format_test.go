@@ -0,0 +1,78 @@
+package fail_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	FormatOrderFailed = fail.ID(1, "FORMAT", 0, false, "FormatOrderFailed")
+	FormatStockEmpty  = fail.ID(0, "FORMAT", 1, false, "FormatStockEmpty")
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: FormatOrderFailed, DefaultMessage: "order %s failed", IsSystem: true})
+	fail.Register(fail.ErrorDefinition{ID: FormatStockEmpty, DefaultMessage: "out of stock"})
+}
+
+func TestFormat_Verbs(t *testing.T) {
+	err := fail.New(FormatOrderFailed).WithArgs("42").With(errors.New("boom"))
+
+	if got := fmt.Sprintf("%v", err); got != err.Error() {
+		t.Errorf("%%v must match Error(), got %q", got)
+	}
+	if got := fmt.Sprintf("%s", err); got != err.Error() {
+		t.Errorf("%%s must match Error(), got %q", got)
+	}
+	if got := fmt.Sprintf("%q", err); got != `"order 42 failed"` {
+		t.Errorf("%%q must quote the rendered message, got %s", got)
+	}
+}
+
+func TestFormat_Verbose(t *testing.T) {
+	inner := fail.New(FormatStockEmpty).
+		AddMeta("sku", "ABC").
+		Validation("qty", "must be positive")
+
+	err := fail.New(FormatOrderFailed).
+		WithArgs("42").
+		Internal("warehouse rejected reservation").
+		AddMeta("zeta", 1).
+		AddMeta("alpha", 2).
+		Trace("reserve").
+		Debug("attempt 1").
+		With(fmt.Errorf("reserve: %w", inner))
+
+	out := fmt.Sprintf("%+v", err)
+
+	wants := []string{
+		"[" + FormatOrderFailed.String() + "] FormatOrderFailed: order 42 failed",
+		"domain:   FORMAT (system error)",
+		"level:    1",
+		"internal: warehouse rejected reservation",
+		"traces:\n",
+		"- reserve",
+		"debug:\n",
+		"- attempt 1",
+		"caused by: reserve: ",
+		"[" + FormatStockEmpty.String() + "] FormatStockEmpty: out of stock",
+		"sku: ABC",
+		"qty: must be positive",
+	}
+	for _, want := range wants {
+		if !strings.Contains(out, want) {
+			t.Errorf("Missing %q in:\n%s", want, out)
+		}
+	}
+
+	if strings.Index(out, "alpha: 2") > strings.Index(out, "zeta: 1") {
+		t.Errorf("Meta must be sorted:\n%s", out)
+	}
+	if strings.HasSuffix(out, "\n") {
+		t.Error("Verbose output must not end with a newline")
+	}
+}
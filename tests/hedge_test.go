@@ -0,0 +1,129 @@
+package fail_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+func TestHedge_SecondAttemptWins(t *testing.T) {
+	var calls atomic.Int32
+	var cancelled atomic.Bool
+
+	start := time.Now()
+	v, err := fail.Hedge(context.Background(), 2, 10*time.Millisecond, func(ctx context.Context) (int, error) {
+		n := calls.Add(1)
+		if n == 1 {
+			// Slow first attempt, must be cancelled once the hedge wins
+			<-ctx.Done()
+			cancelled.Store(true)
+			return 0, ctx.Err()
+		}
+		return 42, nil
+	})
+
+	if err != nil || v != 42 {
+		t.Fatalf("Expected hedged value 42, got %d, %v", v, err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Hedge waited for the slow attempt")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !cancelled.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !cancelled.Load() {
+		t.Error("Losing attempt was not cancelled")
+	}
+}
+
+func TestHedge_AllFail(t *testing.T) {
+	var calls atomic.Int32
+	_, err := fail.Hedge(context.Background(), 3, time.Hour, func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		return 0, errors.New("replica down")
+	})
+
+	if calls.Load() != 3 {
+		t.Errorf("Failures should launch the next attempt immediately, got %d calls", calls.Load())
+	}
+	if !fail.Is(err, fail.MultipleErrors) {
+		t.Fatalf("Expected aggregated MultipleErrors, got %v", err)
+	}
+	if n, _ := fail.GetMeta(err, "error_count"); n != 3 {
+		t.Errorf("Expected 3 aggregated errors, got %v", n)
+	}
+}
+
+func TestHedge_AllFailReachable(t *testing.T) {
+	errReplica := errors.New("replica down")
+	var calls atomic.Int32
+	_, err := fail.Hedge(context.Background(), 3, time.Hour, func(ctx context.Context) (int, error) {
+		if calls.Add(1) == 2 {
+			return 0, fmt.Errorf("replica 2: %w", fail.New(CoreTestID2).System())
+		}
+		return 0, errReplica
+	})
+
+	if !fail.Is(err, fail.MultipleErrors) {
+		t.Fatalf("Expected aggregated MultipleErrors, got %v", err)
+	}
+	if !errors.Is(err, errReplica) {
+		t.Error("Expected errors.Is to reach the generic attempt errors")
+	}
+	if !errors.Is(err, fail.IDError(CoreTestID2)) {
+		t.Error("Expected errors.Is to reach the *Error of an intermediate attempt")
+	}
+
+	// A single attempt returns its error unchanged
+	single := fmt.Errorf("replica 1: %w", errReplica)
+	_, err = fail.Hedge(context.Background(), 1, time.Hour, func(ctx context.Context) (int, error) {
+		return 0, single
+	})
+	if err != single {
+		t.Errorf("Expected the attempt error as-is, got %v", err)
+	}
+}
+
+func TestHedge_ParentCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := fail.Hedge(ctx, 2, time.Hour, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		time.Sleep(5 * time.Millisecond)
+		return 0, ctx.Err()
+	})
+	if !fail.Is(err, fail.RetryAborted) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected RetryAborted wrapping deadline, got %v", err)
+	}
+}
+
+func TestHedge_ParentCancelledInFlight(t *testing.T) {
+	// Cancelling parent cancels the attempts too, their results race parent.Done()
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		var started sync.WaitGroup
+		started.Add(4)
+		go func() {
+			started.Wait()
+			cancel()
+		}()
+
+		_, err := fail.Hedge(ctx, 4, 0, func(ctx context.Context) (int, error) {
+			started.Done()
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		if !fail.Is(err, fail.RetryAborted) || !errors.Is(err, context.Canceled) {
+			t.Fatalf("run %d: expected RetryAborted wrapping context.Canceled, got %v", i, err)
+		}
+	}
+}
@@ -3,11 +3,15 @@ package fail_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/MintzyG/fail/v3"
 )
 
-var HookID = fail.ID(0, "HOOKS", 0, true, "HooksLifecycleError")
+var (
+	HookID      = fail.ID(0, "HOOKS", 0, true, "HooksLifecycleError")
+	HookRetryID = fail.ID(0, "HOOKS", 0, false, "HooksRetryableError")
+)
 
 func TestHooks_Lifecycle(t *testing.T) {
 	// Hooks are global, so we must be careful.
@@ -76,3 +80,49 @@ func TestHooks_PanicRecovery(t *testing.T) {
 		t.Error("Second hook was not called after first hook panicked")
 	}
 }
+
+func TestHooks_Retry(t *testing.T) {
+	fail.Register(fail.ErrorDefinition{ID: HookRetryID, Meta: map[string]any{"retryable": true}})
+
+	var retries, successes, exhausted []int
+	var delays []time.Duration
+	fail.OnRetry(func(e *fail.Error, attempt int, delay time.Duration) {
+		if e.ID == HookRetryID {
+			retries = append(retries, attempt)
+			delays = append(delays, delay)
+		}
+	})
+	fail.OnRetrySuccess(func(e *fail.Error, attempt int, delay time.Duration) {
+		if e.ID == HookRetryID {
+			successes = append(successes, attempt)
+		}
+	})
+	fail.OnRetryExhausted(func(e *fail.Error, attempt int, delay time.Duration) {
+		if e.ID == HookRetryID {
+			exhausted = append(exhausted, attempt)
+		}
+	})
+
+	cfg := fail.RetryConfig{MaxAttempts: 3, Delay: fail.BackoffLinear(time.Millisecond)}
+
+	calls := 0
+	_ = fail.RetryCFG(cfg, func() error {
+		calls++
+		if calls < 3 {
+			return fail.New(HookRetryID)
+		}
+		return nil
+	})
+
+	if len(retries) != 2 || delays[1] != 2*time.Millisecond {
+		t.Errorf("Expected 2 OnRetry calls with planned delays, got %v %v", retries, delays)
+	}
+	if len(successes) != 1 || successes[0] != 3 {
+		t.Errorf("Expected OnRetrySuccess on attempt 3, got %v", successes)
+	}
+
+	_ = fail.RetryCFG(cfg, func() error { return fail.New(HookRetryID) })
+	if len(exhausted) != 1 || exhausted[0] != 3 {
+		t.Errorf("Expected OnRetryExhausted on attempt 3, got %v", exhausted)
+	}
+}
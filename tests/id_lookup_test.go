@@ -0,0 +1,75 @@
+package fail_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var LookupAccountLocked = fail.ID(1, "LOOKUP", 0, true, "LookupAccountLocked")
+
+func TestIDRegistry_Lookup(t *testing.T) {
+	fail.OverrideAllowIDRuntimeRegistrationForTestingOnly(true)
+	defer fail.OverrideAllowIDRuntimeRegistrationForTestingOnly(false)
+
+	id := fail.ID(0, "LOOKUPREG", 0, false, "LookupregCustom")
+
+	got, ok := fail.LookupID(id.String())
+	if !ok || got != id {
+		t.Errorf("Lookup(%q) = %v, %v", id.String(), got, ok)
+	}
+
+	got, ok = fail.LookupIDByName("LookupregCustom")
+	if !ok || got != id {
+		t.Errorf("LookupByName = %v, %v", got, ok)
+	}
+
+	if _, ok := fail.LookupID("0_LOOKUPREG_0099_D"); ok {
+		t.Error("Expected unknown ID string to miss")
+	}
+	if _, ok := fail.NewIDRegistry().Lookup(id.String()); ok {
+		t.Error("Expected isolated registry not to know the ID")
+	}
+}
+
+func TestErrorID_TextRoundTrip(t *testing.T) {
+	type config struct {
+		OnLock fail.ErrorID `json:"on_lock"`
+	}
+
+	data, err := json.Marshal(config{OnLock: LookupAccountLocked})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"on_lock":"1_LOOKUP_0000_S"}` {
+		t.Errorf("Unexpected encoding: %s", data)
+	}
+
+	var decoded config
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.OnLock != LookupAccountLocked || !decoded.OnLock.IsRegistered() {
+		t.Errorf("Expected trusted %v, got %v", LookupAccountLocked, decoded.OnLock)
+	}
+}
+
+func TestErrorID_UnmarshalTextUntrusted(t *testing.T) {
+	var id fail.ErrorID
+	if err := id.UnmarshalText([]byte("2_SOME_OTHER_0007_D")); err != nil {
+		t.Fatalf("UnmarshalText failed: %v", err)
+	}
+	if id.IsRegistered() {
+		t.Error("Unknown IDs must be untrusted")
+	}
+	if id.Domain() != "SOME_OTHER" || id.Level() != 2 || id.Number() != 7 || id.IsStatic() {
+		t.Errorf("Unexpected parse result: %+v", id)
+	}
+
+	for _, bad := range []string{"garbage", "x_AUTH_0001_S", "0_AUTH_0001_X", "0__0001_S"} {
+		if err := id.UnmarshalText([]byte(bad)); !fail.Is(err, fail.IDMalformed) {
+			t.Errorf("Expected IDMalformed for %q, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package fail_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	JSONOuterID = fail.ID(0, "JSON", 0, false, "JSONOuterFailure")
+	JSONInnerID = fail.ID(2, "JSON", 0, true, "JSONInnerStatic")
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: JSONOuterID, DefaultMessage: "outer %s"})
+	fail.Register(fail.ErrorDefinition{ID: JSONInnerID, DefaultMessage: "inner", IsSystem: true})
+}
+
+func TestJSON_RoundTrip(t *testing.T) {
+	inner := fail.New(JSONInnerID).With(errors.New("disk full"))
+	orig := fail.New(JSONOuterID).
+		WithArgs("bob").
+		Internal("secret internal detail").
+		AddMeta("request_id", "abc").
+		AddMeta("callback", func() {}).
+		With(fmt.Errorf("context: %w", inner))
+
+	data, err := json.Marshal(orig)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "secret internal detail") {
+		t.Error("Internal message must not be marshaled")
+	}
+
+	var payload map[string]any
+	_ = json.Unmarshal(data, &payload)
+	if payload["v"] != float64(fail.ErrorJSONVersion) || payload["rendered"] != "outer bob" {
+		t.Errorf("Unexpected payload: %s", data)
+	}
+
+	got, err := fail.UnmarshalError(data)
+	if err != nil {
+		t.Fatalf("UnmarshalError failed: %v", err)
+	}
+	if !got.IsRegistered() || got.ID != JSONOuterID {
+		t.Errorf("Expected registered error with same ID, got %v", got.ID)
+	}
+	if got.GetRendered() != "outer bob" {
+		t.Errorf("Rendered mismatch: %s", got.GetRendered())
+	}
+	if v, _ := fail.GetMeta(got, "request_id"); v != "abc" {
+		t.Errorf("Meta not restored: %v", v)
+	}
+	if !fail.Is(got, JSONOuterID) {
+		t.Error("ID lost")
+	}
+
+	// Cause chain: generic wrapper -> *Error -> generic
+	wrapper := got.Cause
+	if wrapper == nil || wrapper.Error() != "context: "+inner.Error() {
+		t.Fatalf("Generic cause not restored, got %v", wrapper)
+	}
+	restoredInner, ok := fail.As(errors.Unwrap(wrapper))
+	if !ok || restoredInner.ID != JSONInnerID || !restoredInner.IsSystem {
+		t.Errorf("Nested *Error not restored, got %v", errors.Unwrap(wrapper))
+	}
+}
+
+func TestJSON_LibraryMetaKeepsTypes(t *testing.T) {
+	orig := fail.New(JSONOuterID).
+		WithArgs("bob").
+		RetryAfter(2*time.Second).
+		Validation("email", "required")
+	_ = fail.SetMeta(orig, fail.MetaRetryable, true)
+	_ = fail.SetMeta(orig, fail.MetaAttempts, 2)
+	_ = fail.SetMeta(orig, fail.MetaRetryHistory, fail.RetryHistory{
+		{Number: 1, Err: errors.New("timeout"), At: time.Unix(100, 0).UTC(), Delay: time.Second},
+		{Number: 2, Err: errors.New("refused"), At: time.Unix(101, 0).UTC()},
+	})
+
+	data, err := json.Marshal(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fail.UnmarshalError(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d, ok := fail.GetRetryAfter(got); !ok || d != 2*time.Second {
+		t.Errorf("GetRetryAfter after round trip = %v, %v", d, ok)
+	}
+	if !fail.IsRetryableDefault(got) {
+		t.Error("MetaRetryable lost its type")
+	}
+	if n, ok := fail.GetMetaAs(got, fail.MetaAttempts); !ok || n != 2 {
+		t.Errorf("MetaAttempts after round trip = %v, %v", n, ok)
+	}
+	if v, ok := fail.GetValidations(got); !ok || len(v) != 1 || v[0].Field != "email" {
+		t.Errorf("GetValidations after round trip = %v, %v", v, ok)
+	}
+
+	h, ok := fail.GetRetryHistory(got)
+	if !ok || len(h) != 2 {
+		t.Fatalf("GetRetryHistory after round trip = %v, %v", h, ok)
+	}
+	if h[0].Number != 1 || h[0].Delay != time.Second || !h[0].At.Equal(time.Unix(100, 0)) || h[1].Err.Error() != "refused" {
+		t.Errorf("Attempts lost their fields: %+v", h)
+	}
+
+	// Library keys keep their plain names on the wire
+	for _, key := range []string{`"retryable":true`, `"retry_after":2000000000`, `"validations":[`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("Expected %s in %s", key, data)
+		}
+	}
+
+	// Hand-written payloads use the same names
+	manual, err := fail.UnmarshalError([]byte(`{"v":1,"id":"` + JSONOuterID.String() + `","meta":{"retry_after":3000000000,"retryable":true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := fail.GetRetryAfter(manual); d != 3*time.Second || !fail.IsRetryableDefault(manual) {
+		t.Errorf("Library keys not restored: %v", manual.Meta)
+	}
+}
+
+func TestJSON_UnknownAndInvalid(t *testing.T) {
+	data := []byte(`{"v":1,"id":"1_REMOTE_0003_D","name":"RemoteThing","domain":"REMOTE","level":1,"number":3,"message":"remote failure"}`)
+
+	got, err := fail.UnmarshalError(data)
+	if err != nil {
+		t.Fatalf("UnmarshalError failed: %v", err)
+	}
+	if got.IsRegistered() || got.ID.IsRegistered() {
+		t.Error("Unknown IDs must be untrusted")
+	}
+	if got.ID.String() != "1_REMOTE_0003_D" || got.Message != "remote failure" {
+		t.Errorf("Unexpected hydrated error: %s", got.Error())
+	}
+
+	if _, err := fail.UnmarshalError([]byte(`{"v":99,"id":"x"}`)); !fail.Is(err, fail.ErrorPayloadInvalid) {
+		t.Errorf("Expected ErrorPayloadInvalid for future version, got %v", err)
+	}
+	if _, err := fail.UnmarshalError([]byte(`nope`)); !fail.Is(err, fail.ErrorPayloadInvalid) {
+		t.Errorf("Expected ErrorPayloadInvalid for malformed json, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package fail_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	MatchFirstID  = fail.ID(0, "MATCH", 0, true, "MatchFirstError")
+	MatchSecondID = fail.ID(3, "MATCH", 1, true, "MatchSecondError")
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: MatchFirstID, DefaultMessage: "first"})
+	fail.Register(fail.ErrorDefinition{ID: MatchSecondID, DefaultMessage: "second", IsSystem: true})
+}
+
+func TestMatch_FirstMatchWins(t *testing.T) {
+	var hits []string
+
+	fail.Match(fail.New(MatchFirstID)).
+		Case(MatchFirstID, func(e *fail.Error) { hits = append(hits, "case") }).
+		CaseDomain(func(e *fail.Error) { hits = append(hits, "domain") }).
+		Default(func(err error) { hits = append(hits, "default") })
+
+	if len(hits) != 1 || hits[0] != "case" {
+		t.Errorf("Expected only the first case to run, got %v", hits)
+	}
+}
+
+func TestMatch_AnySystemAndDefault(t *testing.T) {
+	matchedAny := false
+	fail.Match(fail.New(MatchSecondID)).
+		Case(MatchFirstID, func(e *fail.Error) { t.Error("Wrong case matched") }).
+		CaseAny(func(e *fail.Error) { matchedAny = true }, MatchFirstID, MatchSecondID)
+	if !matchedAny {
+		t.Error("CaseAny did not match")
+	}
+
+	system := false
+	fail.Match(fail.New(MatchSecondID)).
+		CaseDomain(func(e *fail.Error) { t.Error("Domain case matched system error") }).
+		CaseSystem(func(e *fail.Error) { system = true })
+	if !system {
+		t.Error("CaseSystem did not match")
+	}
+
+	// Generic errors are normalized through From(), unmapped ones are system errors
+	var got error
+	m := fail.Match(errors.New("plain")).
+		Case(MatchFirstID, func(e *fail.Error) { t.Error("Wrong case matched") })
+	m.Default(func(err error) { got = err })
+	if got == nil || got.Error() != "plain" {
+		t.Errorf("Default should receive the original error, got %v", got)
+	}
+
+	fail.Match(nil).Default(func(err error) { t.Error("Default ran for nil error") })
+}
+
+func TestMatch_DomainAndLevel(t *testing.T) {
+	matched := ""
+	fail.Match(fail.New(MatchSecondID)).
+		CaseLevel(0, func(e *fail.Error) { matched = "level0" }).
+		CaseMinLevel(2, func(e *fail.Error) { matched = "min2" })
+	if matched != "min2" {
+		t.Errorf("Expected min level case, got %q", matched)
+	}
+
+	if !fail.Match(fail.New(MatchFirstID)).CaseInDomain("MATCH", func(e *fail.Error) {}).Matched() {
+		t.Error("CaseInDomain did not match")
+	}
+}
+
+func TestMatchValue(t *testing.T) {
+	status := fail.MatchValue[int](fail.New(MatchFirstID)).
+		Case(MatchSecondID, func(e *fail.Error) int { return 500 }).
+		CaseDomain(func(e *fail.Error) int { return 400 }).
+		Default(func(err error) int { return 0 })
+	if status != 400 {
+		t.Errorf("Expected 400, got %d", status)
+	}
+
+	if _, ok := fail.MatchValue[int](fail.New(MatchFirstID)).
+		Case(MatchSecondID, func(e *fail.Error) int { return 1 }).Value(); ok {
+		t.Error("Value reported a match when none happened")
+	}
+}
+
+func TestMatch_Hook(t *testing.T) {
+	var kind any
+	fail.OnMatch(func(e *fail.Error, data map[string]any) {
+		if e.ID == MatchFirstID {
+			kind = data["case"]
+		}
+	})
+
+	fail.Match(fail.New(MatchFirstID)).CaseDomain(func(e *fail.Error) {})
+	if kind != "domain" {
+		t.Errorf("OnMatch hook not fired with case kind, got %v", kind)
+	}
+}
@@ -0,0 +1,115 @@
+package fail_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	MetaKeyOrderFailed = fail.ID(0, "METAKEY", 0, false, "MetakeyOrderFailed")
+	MetaKeyLocked      = fail.ID(0, "METAKEY", 0, true, "MetakeyLocked")
+
+	metaRequestID = fail.NewMetaKey[string]("metakey.request_id")
+	metaAttempt   = fail.NewMetaKey[int]("metakey.attempt")
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: MetaKeyOrderFailed, DefaultMessage: "order failed"})
+	fail.Register(fail.ErrorDefinition{ID: MetaKeyLocked, DefaultMessage: "locked"})
+}
+
+func TestMetaKey_SetGet(t *testing.T) {
+	err := fail.SetMeta(fail.New(MetaKeyOrderFailed), metaRequestID, "abc")
+	err = fail.SetMeta(err, metaAttempt, 2)
+
+	wrapped := fmt.Errorf("ctx: %w", err)
+	if v, ok := fail.GetMetaAs(wrapped, metaRequestID); !ok || v != "abc" {
+		t.Errorf("GetMetaAs = %q, %v", v, ok)
+	}
+	if v, ok := fail.GetMetaAs(wrapped, metaAttempt); !ok || v != 2 {
+		t.Errorf("GetMetaAs = %d, %v", v, ok)
+	}
+
+	// Backwards compatible with the string map
+	if v, _ := fail.GetMeta(err, "metakey.request_id"); v != "abc" {
+		t.Errorf("String access lost the value: %v", v)
+	}
+	_ = err.AddMeta("metakey.attempt", "not an int")
+	if _, ok := fail.GetMetaAs(err, metaAttempt); ok {
+		t.Error("Values of another type must not assert")
+	}
+}
+
+func TestMetaKey_LibraryKeys(t *testing.T) {
+	err := fail.New(MetaKeyOrderFailed).
+		Validation("qty", "required").
+		Trace("checkout").
+		RetryAfter(time.Second)
+	_ = fail.SetMeta(err, fail.MetaRetryable, true)
+
+	if v, _ := fail.GetMetaAs(err, fail.MetaValidations); len(v) != 1 || v[0].Field != "qty" {
+		t.Errorf("Validations not on typed key: %v", v)
+	}
+	if v, _ := fail.GetMetaAs(err, fail.MetaTraces); len(v) != 1 {
+		t.Errorf("Traces not on typed key: %v", v)
+	}
+	if v, _ := fail.GetMetaAs(err, fail.MetaRetryAfter); v != time.Second {
+		t.Errorf("RetryAfter not on typed key: %v", v)
+	}
+	if !fail.IsRetryableDefault(err) {
+		t.Error("MetaRetryable must drive IsRetryableDefault")
+	}
+
+	// Legacy string writes are read by typed helpers
+	legacy := fail.New(MetaKeyOrderFailed).AddMeta("retryable", true)
+	if !fail.IsRetryableDefault(legacy) {
+		t.Error("String key 'retryable' must still be honored")
+	}
+}
+
+func TestMetaKey_StaticAndConflicts(t *testing.T) {
+	err := fail.SetMeta(fail.New(MetaKeyLocked), metaRequestID, "abc")
+	if _, ok := fail.GetMetaAs(err, metaRequestID); ok {
+		t.Error("SetMeta must not mutate static errors")
+	}
+
+	// Same name and type is fine
+	_ = fail.NewMetaKey[string]("metakey.request_id")
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), "already declared") {
+			t.Errorf("Expected conflict panic, got %v", r)
+		}
+	}()
+	_ = fail.NewMetaKey[int]("metakey.request_id")
+}
+
+func TestMetaKey_LibraryKeysKeepNames(t *testing.T) {
+	if fail.MetaAttempts.Name() != "attempts" || fail.MetaValidations.Name() != "validations" {
+		t.Errorf("Library keys must keep their names, got %s and %s", fail.MetaAttempts, fail.MetaValidations)
+	}
+
+	// Typed and string access see the same entries
+	err := fail.New(MetaKeyOrderFailed).Validation("email", "required").AddMeta("attempts", 4)
+	if _, ok := err.Meta["validations"]; !ok {
+		t.Errorf("Validation must store under 'validations', got %v", err.Meta)
+	}
+	if v, ok := fail.GetMetaAs(err, fail.MetaAttempts); !ok || v != 4 {
+		t.Errorf("MetaAttempts must read AddMeta(\"attempts\"), got %v", v)
+	}
+
+	// Library names are not declared, a user key may reuse one with another type
+	reason := fail.NewMetaKey[int]("reason")
+	err = fail.SetMeta(fail.New(MetaKeyOrderFailed), reason, 7)
+	if v, _ := fail.GetMetaAs(err, reason); v != 7 {
+		t.Errorf("User key 'reason' = %v", v)
+	}
+	if _, ok := fail.GetMetaAs(err, fail.MetaReason); ok {
+		t.Error("MetaReason must miss a value of another type")
+	}
+}
@@ -0,0 +1,143 @@
+package fail_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+func TestRetryBudget_Suppresses(t *testing.T) {
+	budget := fail.NewRetryBudget(fail.RetryBudgetConfig{MaxTokens: 4, TokenRatio: 1})
+	cfg := fail.MustNewRetryConfig(fail.WithMaxAttempts(10), fail.WithBudget(budget))
+
+	attempts := 0
+	err := fail.RetryCFG(cfg, func() error {
+		attempts++
+		return fail.New(CoreTestID2).AddMeta("retryable", true)
+	})
+
+	// 4 tokens, retries allowed while tokens > 2: fail(3) retry, fail(2) suppressed
+	if attempts != 2 {
+		t.Errorf("Expected budget to stop after 2 attempts, got %d", attempts)
+	}
+	if !fail.Is(err, CoreTestID2) {
+		t.Fatalf("Expected the original error, got %v", err)
+	}
+	if v, _ := fail.GetMeta(err, "retry_suppressed"); v != true {
+		t.Error("Expected retry_suppressed metadata")
+	}
+
+	// Successes refill the bucket
+	for i := 0; i < 2; i++ {
+		_ = fail.RetryCFG(cfg, func() error { return nil })
+	}
+	if budget.Tokens("") != 4 {
+		t.Errorf("Expected bucket refilled to 4, got %v", budget.Tokens(""))
+	}
+}
+
+var BudgetDBDownID = fail.ID(0, "BUDGET", 0, false, "BudgetDBDown")
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: BudgetDBDownID, DefaultMessage: "database unavailable"})
+}
+
+func TestRetryBudget_DomainIsolation(t *testing.T) {
+	budget := fail.NewRetryBudget(fail.RetryBudgetConfig{MaxTokens: 10, TokenRatio: 1, PerDomain: true})
+	cfg := fail.MustNewRetryConfig(fail.WithMaxAttempts(10), fail.WithBudget(budget))
+
+	// Drain the BUDGET bucket: fail(9) ... fail(5) suppressed
+	_ = fail.RetryCFG(cfg, func() error {
+		return fail.New(BudgetDBDownID).AddMeta("retryable", true)
+	})
+	drained := budget.Tokens("BUDGET")
+	if drained != 5 {
+		t.Fatalf("Expected BUDGET bucket drained to 5, got %v", drained)
+	}
+
+	// First-attempt successes of other calls have no domain and credit nothing
+	for i := 0; i < 10; i++ {
+		_ = fail.RetryCFG(cfg, func() error { return nil })
+	}
+	if budget.Tokens("BUDGET") != drained {
+		t.Errorf("Unrelated successes refilled the BUDGET bucket to %v", budget.Tokens("BUDGET"))
+	}
+
+	// A CORE failure followed by a success only touches CORE
+	attempts := 0
+	_ = fail.RetryCFG(cfg, func() error {
+		attempts++
+		if attempts == 1 {
+			return fail.New(CoreTestID2).AddMeta("retryable", true)
+		}
+		return nil
+	})
+	if budget.Tokens("CORE") != 10 {
+		t.Errorf("Expected CORE bucket back to 10, got %v", budget.Tokens("CORE"))
+	}
+	if budget.Tokens("BUDGET") != drained {
+		t.Errorf("CORE success credited the BUDGET bucket, got %v", budget.Tokens("BUDGET"))
+	}
+}
+
+func TestRetryBudget_SuccessCreditsOnce(t *testing.T) {
+	budget := fail.NewRetryBudget(fail.RetryBudgetConfig{MaxTokens: 100, TokenRatio: 1, PerDomain: true})
+	cfg := fail.MustNewRetryConfig(fail.WithMaxAttempts(10), fail.WithBudget(budget))
+
+	attempts := 0
+	_ = fail.RetryCFG(cfg, func() error {
+		attempts++
+		if attempts <= 3 {
+			return fail.New(BudgetDBDownID).AddMeta("retryable", true)
+		}
+		return nil
+	})
+
+	// 3 failures take 3 tokens, the success gives back 1 once
+	if budget.Tokens("BUDGET") != 98 {
+		t.Errorf("Expected 98 tokens, got %v", budget.Tokens("BUDGET"))
+	}
+}
+
+func TestRetryBudget_SuppressedKeepsChain(t *testing.T) {
+	budget := fail.NewRetryBudget(fail.RetryBudgetConfig{MaxTokens: 2, TokenRatio: 1})
+	cfg := fail.MustNewRetryConfig(fail.WithMaxAttempts(10), fail.WithBudget(budget))
+
+	var last *fail.Error
+	err := fail.RetryCFG(cfg, func() error {
+		last = fail.New(BudgetDBDownID).AddMeta("retryable", true)
+		return fmt.Errorf("load user: %w", last)
+	})
+
+	if !strings.HasPrefix(err.Error(), "load user: ") {
+		t.Errorf("Expected the caller's wrapping to be kept, got %q", err.Error())
+	}
+	if !errors.Is(err, last) || !fail.Is(err, BudgetDBDownID) {
+		t.Error("Expected the original error to stay reachable")
+	}
+	if v, _ := fail.GetMeta(err, "retry_suppressed"); v != true {
+		t.Error("Expected retry_suppressed metadata")
+	}
+	if _, ok := fail.GetMeta(last, "retry_suppressed"); ok {
+		t.Error("The caller's error must not be modified")
+	}
+}
+
+func TestRetryBudget_PerDomain(t *testing.T) {
+	budget := fail.NewRetryBudget(fail.RetryBudgetConfig{MaxTokens: 2, PerDomain: true})
+	cfg := fail.MustNewRetryConfig(fail.WithMaxAttempts(3), fail.WithBudget(budget))
+
+	_ = fail.RetryCFG(cfg, func() error {
+		return fail.New(CoreTestID2).AddMeta("retryable", true)
+	})
+
+	if budget.Tokens("CORE") >= 2 {
+		t.Error("CORE bucket should have been charged")
+	}
+	if budget.Tokens("OTHER") != 2 {
+		t.Error("Other domains should keep a full bucket")
+	}
+}
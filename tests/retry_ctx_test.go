@@ -0,0 +1,96 @@
+package fail_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+func TestRetryCtx_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := fail.RetryConfig{
+		MaxAttempts: 10,
+		Delay:       fail.BackoffConstant(time.Hour),
+	}
+
+	attempts := 0
+	var attemptErr error
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := fail.RetryCtxCFG(ctx, cfg, func(ctx context.Context) error {
+		attempts++
+		attemptErr = fail.New(CoreTestID2).AddMeta("retryable", true)
+		return attemptErr
+	})
+
+	if time.Since(start) > time.Second {
+		t.Fatal("RetryCtxCFG kept sleeping after cancellation")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+	if !fail.Is(err, fail.RetryAborted) {
+		t.Fatalf("Expected RetryAborted, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Error("RetryAborted should wrap ctx.Err()")
+	}
+	if !errors.Is(err, attemptErr) {
+		t.Error("RetryAborted should wrap the last attempt error")
+	}
+}
+
+func TestRetryCtx_DeadlineSkipsSleep(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	cfg := fail.RetryConfig{
+		MaxAttempts: 3,
+		Delay:       fail.BackoffConstant(time.Hour),
+	}
+
+	start := time.Now()
+	err := fail.RetryCtxCFG(ctx, cfg, func(ctx context.Context) error {
+		return fail.New(CoreTestID2).AddMeta("retryable", true)
+	})
+
+	if time.Since(start) > 40*time.Millisecond {
+		t.Error("Sleep overshooting the deadline should be skipped immediately")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRetryValueCtx_PassesContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "v")
+
+	val, err := fail.RetryValueCtx(ctx, func(ctx context.Context) (string, error) {
+		return ctx.Value(key{}).(string), nil
+	})
+	if err != nil || val != "v" {
+		t.Errorf("Expected ctx value to reach attempt, got %q, %v", val, err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	err = fail.RetryCtx(cancelled, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	if called {
+		t.Error("Attempt ran with an already cancelled context")
+	}
+	if !fail.Is(err, fail.RetryAborted) {
+		t.Errorf("Expected RetryAborted, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package fail_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+func TestRetry_ExhaustedHistory(t *testing.T) {
+	cfg := fail.MustNewRetryConfig(
+		fail.WithMaxAttempts(3),
+		fail.WithBackoff(fail.BackoffConstant(time.Millisecond)),
+		fail.WithExhaustedError(true),
+	)
+
+	var attemptErrs []error
+	err := fail.RetryCFG(cfg, func() error {
+		e := fail.New(CoreTestID2).Msgf("attempt %d", len(attemptErrs)+1).AddMeta("retryable", true)
+		attemptErrs = append(attemptErrs, e)
+		return e
+	})
+
+	if !fail.Is(err, fail.RetryExhausted) {
+		t.Fatalf("Expected RetryExhausted, got %v", err)
+	}
+	if n, _ := fail.GetMeta(err, "attempts"); n != 3 {
+		t.Errorf("Expected 3 attempts in meta, got %v", n)
+	}
+
+	history, ok := fail.GetRetryHistory(err)
+	if !ok || len(history) != 3 {
+		t.Fatalf("Expected 3 history entries, got %v", history)
+	}
+	if history[0].Delay != time.Millisecond || history[2].Delay != 0 {
+		t.Errorf("Unexpected delays: %v, %v", history[0].Delay, history[2].Delay)
+	}
+	if history[0].At.IsZero() || history[1].At.Before(history[0].At) {
+		t.Error("Attempt timestamps not recorded in order")
+	}
+
+	for i, ae := range attemptErrs {
+		if !errors.Is(err, ae) {
+			t.Errorf("errors.Is did not find attempt %d error", i+1)
+		}
+	}
+}
+
+func TestRetry_ExhaustedOptIn(t *testing.T) {
+	cfg := fail.RetryConfig{MaxAttempts: 2}
+
+	err := fail.RetryCFG(cfg, func() error {
+		return fail.New(CoreTestID2).AddMeta("retryable", true)
+	})
+	if !fail.Is(err, CoreTestID2) || fail.Is(err, fail.RetryExhausted) {
+		t.Errorf("Without opt-in the last error should be returned, got %v", err)
+	}
+
+	// Non retryable errors are returned as-is even with opt-in
+	cfg.ExhaustedError = true
+	plain := errors.New("not retryable")
+	if err := fail.RetryCFG(cfg, func() error { return plain }); err != plain {
+		t.Errorf("Expected non retryable error as-is, got %v", err)
+	}
+}
+
+// retryHistoryTimeout is only returned by an intermediate attempt
+type retryHistoryTimeout struct{ attempt int }
+
+func (e *retryHistoryTimeout) Error() string { return "timeout" }
+
+func TestRetry_HistoryReachableFromTopLevel(t *testing.T) {
+	cfg := fail.MustNewRetryConfig(
+		fail.WithMaxAttempts(3),
+		fail.WithBackoff(fail.BackoffConstant(time.Millisecond)),
+		fail.WithExhaustedError(true),
+	)
+
+	attempt := 0
+	sentinel := errors.New("first attempt")
+	err := fail.RetryCFG(cfg, func() error {
+		attempt++
+		e := fail.New(CoreTestID2).AddMeta("retryable", true)
+		switch attempt {
+		case 1:
+			return e.With(sentinel)
+		case 2:
+			return e.With(&retryHistoryTimeout{attempt: 2})
+		}
+		return e
+	})
+
+	top, ok := fail.As(err)
+	if !ok || !fail.Is(top, fail.RetryExhausted) {
+		t.Fatalf("Expected a top-level RetryExhausted *Error, got %v", err)
+	}
+	if !errors.Is(top, sentinel) {
+		t.Error("errors.Is must reach the error of the first attempt")
+	}
+	var timeout *retryHistoryTimeout
+	if !errors.As(top, &timeout) || timeout.attempt != 2 {
+		t.Errorf("errors.As must reach the error of an intermediate attempt, got %v", timeout)
+	}
+
+	// Aborted retries keep the history reachable too
+	ctx, cancel := context.WithCancel(context.Background())
+	attempt = 0
+	err = fail.RetryCtxCFG(ctx, cfg, func(context.Context) error {
+		attempt++
+		if attempt == 2 {
+			cancel()
+		}
+		if attempt == 1 {
+			return fail.New(CoreTestID2).AddMeta("retryable", true).With(sentinel)
+		}
+		return fail.New(CoreTestID2).AddMeta("retryable", true)
+	})
+	if !fail.Is(err, fail.RetryAborted) || !errors.Is(err, context.Canceled) || !errors.Is(err, sentinel) {
+		t.Errorf("Expected RetryAborted reaching ctx error and the first attempt error, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package fail_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+func TestNewRetryConfig_Options(t *testing.T) {
+	var seen []int
+	cfg, err := fail.NewRetryConfig(
+		fail.WithMaxAttempts(3),
+		fail.WithBackoff(fail.BackoffExponential(time.Hour)),
+		fail.WithMaxDelay(time.Millisecond),
+		fail.WithOnAttempt(func(attempt int, err error) {
+			seen = append(seen, attempt)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewRetryConfig failed: %v", err)
+	}
+
+	start := time.Now()
+	err = fail.RetryCFG(cfg, func() error {
+		return fail.New(CoreTestID2).AddMeta("retryable", true)
+	})
+	if err == nil {
+		t.Error("RetryCFG returned nil on failure")
+	}
+	if len(seen) != 3 || seen[2] != 3 {
+		t.Errorf("Expected OnAttempt for 3 attempts, got %v", seen)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("MaxDelay did not cap the backoff")
+	}
+}
+
+func TestNewRetryConfig_MaxElapsed(t *testing.T) {
+	attempts := 0
+	cfg := fail.MustNewRetryConfig(
+		fail.WithMaxAttempts(10),
+		fail.WithBackoff(fail.BackoffConstant(20*time.Millisecond)),
+		fail.WithMaxElapsed(30*time.Millisecond),
+	)
+
+	_ = fail.RetryCFG(cfg, func() error {
+		attempts++
+		return fail.New(CoreTestID2).AddMeta("retryable", true)
+	})
+	if attempts != 2 {
+		t.Errorf("Expected MaxElapsed to stop after 2 attempts, got %d", attempts)
+	}
+}
+
+func TestNewRetryConfig_Invalid(t *testing.T) {
+	_, err := fail.NewRetryConfig(fail.WithMaxAttempts(0))
+	if !fail.Is(err, fail.RetryConfigInvalid) {
+		t.Fatalf("Expected RetryConfigInvalid, got %v", err)
+	}
+	if opt, _ := fail.GetMeta(err, "option"); opt != "WithMaxAttempts" {
+		t.Errorf("Expected option meta, got %v", opt)
+	}
+
+	if _, err := fail.NewRetryConfig(fail.WithBackoff(nil)); !fail.Is(err, fail.RetryConfigInvalid) {
+		t.Errorf("Expected RetryConfigInvalid for nil backoff, got %v", err)
+	}
+
+	expectPanic(t, "invalid retry config", func() {
+		fail.MustNewRetryConfig(fail.WithMaxDelay(-time.Second))
+	})
+}
@@ -0,0 +1,136 @@
+package fail_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	PolicyDeadlockID = fail.ID(0, "POLICY", 0, true, "PolicyDeadlock")
+	PolicyFatalID    = fail.ID(0, "POLICY", 0, false, "PolicyFatalState")
+	PolicyBackoffID  = fail.ID(0, "POLICY", 1, true, "PolicyBackoffTimeout")
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{
+		ID:             PolicyDeadlockID,
+		DefaultMessage: "deadlock detected",
+		RetryPolicy: &fail.RetryPolicy{
+			Retryable:   true,
+			MaxAttempts: 4,
+			Delay:       fail.BackoffConstant(time.Millisecond),
+		},
+	})
+	fail.Register(fail.ErrorDefinition{ID: PolicyFatalID, DefaultMessage: "fatal"})
+	fail.Register(fail.ErrorDefinition{ID: PolicyBackoffID, DefaultMessage: "backend timed out"})
+}
+
+func TestRetryPolicy_FromDefinition(t *testing.T) {
+	attempts := 0
+	// Config leaves MaxAttempts and Delay unset, the policy for the ID decides
+	err := fail.RetryCFG(fail.RetryConfig{}, func() error {
+		attempts++
+		return fail.New(PolicyDeadlockID)
+	})
+
+	if !fail.Is(err, PolicyDeadlockID) {
+		t.Errorf("Expected deadlock error, got %v", err)
+	}
+	if attempts != 4 {
+		t.Errorf("Expected policy MaxAttempts of 4, got %d", attempts)
+	}
+}
+
+func TestRetryPolicy_ExplicitMaxAttemptsWins(t *testing.T) {
+	configs := map[string]fail.RetryConfig{
+		"struct":  {MaxAttempts: 1},
+		"options": fail.MustNewRetryConfig(fail.WithMaxAttempts(2)),
+	}
+	want := map[string]int{"struct": 1, "options": 2}
+
+	for name, cfg := range configs {
+		attempts := 0
+		_ = fail.RetryCFG(cfg, func() error {
+			attempts++
+			return fail.New(PolicyDeadlockID)
+		})
+		if attempts != want[name] {
+			t.Errorf("%s: explicit MaxAttempts %d must cap the policy's 4, got %d attempts", name, want[name], attempts)
+		}
+	}
+
+	// Without WithMaxAttempts the builder's default gives way to the policy
+	attempts := 0
+	_ = fail.RetryCFG(fail.MustNewRetryConfig(), func() error {
+		attempts++
+		return fail.New(PolicyDeadlockID)
+	})
+	if attempts != 4 {
+		t.Errorf("Expected policy MaxAttempts of 4 over the default config, got %d", attempts)
+	}
+}
+
+func TestRetryPolicy_ExplicitBackoffWins(t *testing.T) {
+	policyDelays, configDelays := 0, 0
+	if err := fail.SetRetryPolicy(PolicyBackoffID, fail.RetryPolicy{
+		Retryable: true,
+		Delay: func(int) time.Duration {
+			policyDelays++
+			return time.Millisecond
+		},
+	}); err != nil {
+		t.Fatalf("SetRetryPolicy failed: %v", err)
+	}
+	explicit := func(int) time.Duration {
+		configDelays++
+		return time.Millisecond
+	}
+
+	configs := map[string]fail.RetryConfig{
+		"struct":  {MaxAttempts: 3, Delay: explicit},
+		"options": fail.MustNewRetryConfig(fail.WithMaxAttempts(3), fail.WithBackoff(explicit)),
+	}
+	for name, cfg := range configs {
+		policyDelays, configDelays = 0, 0
+		_ = fail.RetryCFG(cfg, func() error { return fail.New(PolicyBackoffID) })
+		if configDelays != 2 || policyDelays != 0 {
+			t.Errorf("%s: explicit backoff must win over the policy, got %d config and %d policy delays",
+				name, configDelays, policyDelays)
+		}
+	}
+
+	// Without a backoff the policy's Delay is used
+	policyDelays, configDelays = 0, 0
+	_ = fail.RetryCFG(fail.MustNewRetryConfig(fail.WithMaxAttempts(3)), func() error {
+		return fail.New(PolicyBackoffID)
+	})
+	if policyDelays != 2 {
+		t.Errorf("Expected the policy Delay for the 2 retries, got %d", policyDelays)
+	}
+}
+
+func TestRetryPolicy_SetRetryPolicy(t *testing.T) {
+	if fail.IsRetryableDefault(fail.New(PolicyFatalID)) {
+		t.Error("Error without policy or meta should not be retryable")
+	}
+
+	if err := fail.SetRetryPolicy(PolicyFatalID, fail.RetryPolicy{Retryable: true}); err != nil {
+		t.Fatalf("SetRetryPolicy failed: %v", err)
+	}
+	defer fail.SetRetryPolicy(PolicyFatalID, fail.RetryPolicy{})
+
+	if !fail.IsRetryableDefault(fail.New(PolicyFatalID)) {
+		t.Error("Policy should make the error retryable")
+	}
+
+	// Explicit instance meta still wins over the policy
+	if fail.IsRetryableDefault(fail.New(PolicyFatalID).AddMeta("retryable", false)) {
+		t.Error("Meta retryable=false should override policy")
+	}
+
+	if p, ok := fail.GetRetryPolicy(PolicyFatalID); !ok || !p.Retryable {
+		t.Errorf("GetRetryPolicy returned %v, %v", p, ok)
+	}
+}
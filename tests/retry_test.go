@@ -76,3 +76,26 @@ func TestBackoffStrategies(t *testing.T) {
 		t.Error("Exponential backoff wrong")
 	}
 }
+
+func TestRetry_RetryAfterHint(t *testing.T) {
+	cfg := fail.RetryConfig{
+		MaxAttempts:   2,
+		Delay:         fail.BackoffConstant(time.Hour),
+		MaxRetryAfter: 5 * time.Millisecond,
+	}
+
+	start := time.Now()
+	_ = fail.RetryCFG(cfg, func() error {
+		return fail.New(CoreTestID2).AddMeta("retryable", true).RetryAfter(time.Minute)
+	})
+	elapsed := time.Since(start)
+
+	if elapsed < 5*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected retry_after hint capped at 5ms to win over backoff, took %v", elapsed)
+	}
+
+	d, ok := fail.GetRetryAfter(fail.New(CoreTestID2).RetryAfter(time.Second))
+	if !ok || d != time.Second {
+		t.Errorf("GetRetryAfter returned %v, %v", d, ok)
+	}
+}
@@ -0,0 +1,110 @@
+package fail_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+	failslog "github.com/MintzyG/fail/v3/plugins/logging/slog"
+)
+
+var (
+	SlogPaymentDeclined = fail.ID(1, "SLOG", 0, false, "SlogPaymentDeclined")
+	SlogGatewayDown     = fail.ID(3, "SLOG", 1, false, "SlogGatewayDown")
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: SlogPaymentDeclined, DefaultMessage: "payment declined"})
+	fail.Register(fail.ErrorDefinition{ID: SlogGatewayDown, DefaultMessage: "gateway down", IsSystem: true})
+}
+
+type slogCtxKey struct{}
+
+func decodeRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Invalid log output %q: %v", buf.String(), err)
+	}
+	return record
+}
+
+func TestLogValue_Group(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	err := fail.New(SlogGatewayDown).
+		AddMeta("region", "eu").
+		With(fail.New(SlogPaymentDeclined).With(errors.New("card expired")))
+	logger.Error("charge failed", "error", err)
+
+	group := decodeRecord(t, &buf)["error"].(map[string]any)
+	if group["id"] != SlogGatewayDown.String() || group["domain"] != "SLOG" || group["level"] != float64(3) || group["system"] != true {
+		t.Errorf("Unexpected group: %v", group)
+	}
+	if group["message"] != "gateway down" {
+		t.Errorf("Unexpected message: %v", group["message"])
+	}
+	if meta := group["meta"].(map[string]any); meta["region"] != "eu" {
+		t.Errorf("Meta lost: %v", meta)
+	}
+
+	cause := group["cause"].(map[string]any)
+	if cause["id"] != SlogPaymentDeclined.String() || cause["cause"] != "card expired" {
+		t.Errorf("Cause chain lost: %v", cause)
+	}
+}
+
+func TestSlogPlugin_Options(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	logger := failslog.New(
+		failslog.WithLogger(base),
+		failslog.WithMetaKeys("request_id"),
+		failslog.WithInternalMessage(),
+		failslog.WithLevels(map[int]slog.Level{1: slog.LevelDebug}),
+		failslog.WithContextAttrs(func(ctx context.Context) []slog.Attr {
+			if v, ok := ctx.Value(slogCtxKey{}).(string); ok {
+				return []slog.Attr{slog.String("tenant", v)}
+			}
+			return nil
+		}),
+	)
+
+	err := fail.New(SlogPaymentDeclined).
+		Internal("issuer returned 05").
+		AddMeta("request_id", "r-1").
+		AddMeta("card_number", "4111")
+
+	ctx := context.WithValue(context.Background(), slogCtxKey{}, "acme")
+	logger.LogCtx(ctx, err)
+
+	record := decodeRecord(t, &buf)
+	if record["level"] != "DEBUG" || record["msg"] != "payment declined" || record["tenant"] != "acme" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+
+	group := record["error"].(map[string]any)
+	if group["internal"] != "issuer returned 05" {
+		t.Errorf("Internal message missing: %v", group)
+	}
+	meta := group["meta"].(map[string]any)
+	if meta["request_id"] != "r-1" {
+		t.Errorf("Allowed meta missing: %v", meta)
+	}
+	if _, ok := meta["card_number"]; ok {
+		t.Error("Filtered meta must not be logged")
+	}
+
+	// Default mapping: level 3 is an error
+	buf.Reset()
+	logger.Log(fail.New(SlogGatewayDown))
+	if record := decodeRecord(t, &buf); record["level"] != "ERROR" {
+		t.Errorf("Expected ERROR, got %v", record["level"])
+	}
+}
@@ -0,0 +1,75 @@
+package fail_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MintzyG/fail/v3"
+)
+
+var (
+	StackDomainMiss = fail.ID(0, "STACK", 0, false, "StackDomainMiss")
+	StackIOFailure  = fail.ID(3, "STACK", 1, false, "StackIOFailure")
+)
+
+func init() {
+	fail.Register(fail.ErrorDefinition{ID: StackDomainMiss, DefaultMessage: "not found"})
+	fail.Register(fail.ErrorDefinition{ID: StackIOFailure, DefaultMessage: "io failure", IsSystem: true})
+}
+
+func stackHelperWrap() *fail.Error {
+	return fail.Wrap(StackIOFailure, errors.New("disk"))
+}
+
+func TestStack_CaptureByLevel(t *testing.T) {
+	fail.CaptureStackAtLevel(2)
+	defer fail.CaptureStackAtLevel(-1)
+
+	if frames := fail.New(StackDomainMiss).StackTrace(); frames != nil {
+		t.Errorf("Level 0 errors must not capture, got %d frames", len(frames))
+	}
+
+	err := stackHelperWrap()
+	frames := err.StackTrace()
+	if len(frames) == 0 {
+		t.Fatal("Expected captured frames")
+	}
+	if !strings.HasSuffix(frames[0].Function, "stackHelperWrap") {
+		t.Errorf("First frame must be the caller outside the library, got %s", frames[0].Function)
+	}
+	for _, f := range frames {
+		if f.Package == "github.com/MintzyG/fail/v3" {
+			t.Errorf("Library frame not filtered: %s", f.Function)
+		}
+	}
+	if !strings.HasPrefix(frames[0].Package, "github.com/MintzyG/fail/v3/tests") || frames[0].Line == 0 {
+		t.Errorf("Unexpected frame: %+v", frames[0])
+	}
+
+	if got, ok := fail.GetStackTrace(fmt.Errorf("ctx: %w", err)); !ok || len(got) != len(frames) {
+		t.Error("GetStackTrace must find the stack through wrappers")
+	}
+	if !strings.Contains(fmt.Sprintf("%+v", err), "stack:\n") {
+		t.Error("Verbose format must print the stack")
+	}
+}
+
+func TestStack_CaptureByID(t *testing.T) {
+	fail.CaptureStackAtLevel(2)
+	fail.CaptureStackForID(StackDomainMiss, true)
+	fail.CaptureStackForID(StackIOFailure, false)
+	defer func() {
+		fail.CaptureStackAtLevel(-1)
+		fail.CaptureStackForID(StackDomainMiss, false)
+		fail.CaptureStackForID(StackIOFailure, false)
+	}()
+
+	if len(fail.New(StackDomainMiss).StackTrace()) == 0 {
+		t.Error("Per ID opt-in must capture")
+	}
+	if fail.New(StackIOFailure).StackTrace() != nil {
+		t.Error("Per ID opt-out must override the level")
+	}
+}
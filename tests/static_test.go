@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/MintzyG/fail/v3"
 )
@@ -98,3 +99,25 @@ func TestStaticErrorBuilders(t *testing.T) {
 		}
 	})
 }
+
+func TestStaticErrorRetryAfter(t *testing.T) {
+	fail.OverrideAllowIDRuntimeRegistrationForTestingOnly(true)
+	defer fail.OverrideAllowIDRuntimeRegistrationForTestingOnly(false)
+
+	fail.AllowStaticMutations(false, false)
+	fail.AllowRuntimePanics(false)
+
+	staticID := fail.ID(0, "STAT", 1, true, "StatThrottledError")
+	fail.Register(fail.ErrorDefinition{
+		ID:             staticID,
+		DefaultMessage: "too many requests",
+	})
+
+	err := fail.New(staticID).RetryAfter(time.Second)
+	if d, ok := fail.GetRetryAfter(err); !ok || d != time.Second {
+		t.Errorf("expected retry_after hint of 1s on static error, got %v, %v", d, ok)
+	}
+	if err.Message != "too many requests" {
+		t.Errorf("expected message to remain 'too many requests', got '%s'", err.Message)
+	}
+}